@@ -0,0 +1,166 @@
+package avro
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheStats are the statistics of the encoder and decoder caches of an API.
+type CacheStats struct {
+	// Hits is the number of encoders and decoders found in the cache.
+	Hits uint64
+
+	// Misses is the number of encoders and decoders that were not found in
+	// the cache and were created.
+	Misses uint64
+
+	// Evictions is the number of encoders and decoders evicted from the cache,
+	// because the cache was full or they expired.
+	Evictions uint64
+
+	// Entries is the number of encoders and decoders in the cache.
+	Entries int
+}
+
+// codecCache caches encoders or decoders by cacheKey. If it has a maximum
+// number of entries or a TTL, entries are evicted in least recently used order.
+type codecCache struct {
+	maxEntries int
+	ttl        time.Duration
+	now        func() time.Time
+
+	// unbounded is used if there is no maximum number of entries or TTL.
+	unbounded sync.Map // map[cacheKey]any
+	count     atomic.Int64
+
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	lru     *list.List // *cacheEntry, most recently used first
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+type cacheEntry struct {
+	key  cacheKey
+	val  any
+	used time.Time
+}
+
+func newCodecCache(maxEntries int, ttl time.Duration) *codecCache {
+	return &codecCache{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		now:        time.Now,
+		entries:    map[cacheKey]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+func (c *codecCache) bounded() bool {
+	return c.maxEntries > 0 || c.ttl > 0
+}
+
+// Load returns the cached value of key.
+func (c *codecCache) Load(key cacheKey) (any, bool) {
+	if !c.bounded() {
+		val, ok := c.unbounded.Load(key)
+		if ok {
+			c.hits.Add(1)
+		}
+		return val, ok
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if c.ttl > 0 {
+		now := c.now()
+		if c.expired(entry, now) {
+			c.remove(elem)
+			return nil, false
+		}
+		entry.used = now
+	}
+	c.lru.MoveToFront(elem)
+	c.hits.Add(1)
+	return entry.val, true
+}
+
+// Store caches the value of key, evicting the least recently used entries
+// if the cache is full. Storing a value counts as a cache miss, as values are
+// only stored once they were not found.
+func (c *codecCache) Store(key cacheKey, val any) {
+	c.misses.Add(1)
+
+	if !c.bounded() {
+		if _, loaded := c.unbounded.Swap(key, val); !loaded {
+			c.count.Add(1)
+		}
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var now time.Time
+	if c.ttl > 0 {
+		now = c.now()
+	}
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.val = val
+		entry.used = now
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, val: val, used: now})
+	for elem := c.lru.Back(); elem != nil; elem = c.lru.Back() {
+		if !c.expired(elem.Value.(*cacheEntry), now) && (c.maxEntries <= 0 || c.lru.Len() <= c.maxEntries) {
+			break
+		}
+		c.remove(elem)
+	}
+}
+
+func (c *codecCache) expired(entry *cacheEntry, now time.Time) bool {
+	return c.ttl > 0 && now.Sub(entry.used) > c.ttl
+}
+
+func (c *codecCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+	c.evictions.Add(1)
+}
+
+// Len returns the number of cached entries.
+func (c *codecCache) Len() int {
+	if !c.bounded() {
+		return int(c.count.Load())
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// CacheStats returns the statistics of the encoder and decoder caches.
+func (c *frozenConfig) CacheStats() CacheStats {
+	var stats CacheStats
+	for _, cache := range []*codecCache{c.encoderCache, c.decoderCache} {
+		stats.Hits += cache.hits.Load()
+		stats.Misses += cache.misses.Load()
+		stats.Evictions += cache.evictions.Load()
+		stats.Entries += cache.Len()
+	}
+	return stats
+}
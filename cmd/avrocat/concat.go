@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+)
+
+func runConcat(flgs *flag.FlagSet, args []string, stdout, stderr io.Writer) int {
+	var codec, readerSchemaPath string
+	flgs.StringVar(&codec, "codec", "", "The compression codec of the output. Defaults to the codec of the first input.")
+	flgs.StringVar(&readerSchemaPath, "reader-schema", "",
+		"The schema of the output. Inputs with a different schema are resolved against it. "+
+			"Defaults to the schema of the inputs, which must then all be the same.")
+	if err := flgs.Parse(args[1:]); err != nil {
+		return 1
+	}
+	if flgs.NArg() < 2 {
+		_, _ = fmt.Fprintf(stderr, "Error: expected at least 2 arguments, got %d\n", flgs.NArg())
+		flgs.Usage()
+		return 1
+	}
+
+	var readerSchema avro.Schema
+	if readerSchemaPath != "" {
+		var err error
+		readerSchema, err = avro.ParseFiles(readerSchemaPath)
+		if err != nil {
+			return printError(stderr, err)
+		}
+	}
+
+	if err := concat(flgs.Arg(0), flgs.Args()[1:], codecFlag(codec), readerSchema); err != nil {
+		return printError(stderr, err)
+	}
+	return 0
+}
+
+func runRecodec(flgs *flag.FlagSet, args []string, stdout, stderr io.Writer) int {
+	var codec string
+	flgs.StringVar(&codec, "codec", "", "The compression codec of the output. Required.")
+	if !parseArgs(flgs, args, 2, stderr) {
+		return 1
+	}
+	if codec == "" {
+		_, _ = fmt.Fprintln(stderr, "Error: the codec is required")
+		flgs.Usage()
+		return 1
+	}
+
+	if err := concat(flgs.Arg(1), []string{flgs.Arg(0)}, codecFlag(codec), nil); err != nil {
+		return printError(stderr, err)
+	}
+	return 0
+}
+
+// codecFlag returns the codec name of a codec flag value.
+func codecFlag(s string) ocf.CodecName {
+	if s == "zstd" {
+		return ocf.ZStandard
+	}
+	return ocf.CodecName(s)
+}
+
+// concat writes the blocks of the container files at paths to a new container
+// file at out.
+//
+// Blocks are copied as is when their schema and codec match the output, and
+// recompressed when only their codec differs. If a reader schema is given,
+// blocks with a different schema are decoded with the schema resolved against
+// it and encoded again.
+func concat(out string, paths []string, codec ocf.CodecName, readerSchema avro.Schema) error {
+	var w *ocf.BlockWriter
+	defer func() {
+		if w != nil {
+			_ = w.Close()
+		}
+	}()
+
+	f, err := os.Create(out) //nolint:gosec // The path is provided by the user.
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	for _, path := range paths {
+		err = withBlockReader(path, func(r *ocf.BlockReader) error {
+			if w == nil {
+				var err error
+				if w, err = newConcatWriter(f, r, codec, readerSchema); err != nil {
+					return err
+				}
+			}
+			return copyBlocks(w, r, readerSchema != nil)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	err = w.Close()
+	w = nil
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// newConcatWriter returns a block writer with the schema, codec and user
+// metadata of the first input, unless overridden.
+func newConcatWriter(f io.Writer, r *ocf.BlockReader, codec ocf.CodecName, schema avro.Schema) (*ocf.BlockWriter, error) {
+	if codec == "" {
+		codec = r.CodecName()
+	}
+	if schema == nil {
+		schema = r.Schema()
+	}
+
+	meta := map[string][]byte{}
+	for k, v := range r.Metadata() {
+		if !strings.HasPrefix(k, "avro.") {
+			meta[k] = v
+		}
+	}
+
+	return ocf.NewBlockWriter(schema, f, ocf.WithCodec(codec), ocf.WithMetadata(meta))
+}
+
+// copyBlocks copies the blocks of r to w, resolving the schema of r against
+// the schema of w if they differ and resolve is set.
+func copyBlocks(w *ocf.BlockWriter, r *ocf.BlockReader, resolve bool) error {
+	var resolved avro.Schema
+	if r.Schema().Fingerprint() != w.Schema().Fingerprint() {
+		if !resolve {
+			return errors.New("schema differs from the output schema, set a reader schema to resolve it")
+		}
+		var err error
+		resolved, err = avro.NewSchemaCompatibility().Resolve(w.Schema(), r.Schema())
+		if err != nil {
+			return fmt.Errorf("schema differs from the output schema and cannot be resolved: %w", err)
+		}
+	}
+
+	for {
+		b, err := r.ReadBlock()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if resolved == nil && b.Codec == w.CodecName() {
+			if err = w.WriteBlock(b); err != nil {
+				return err
+			}
+			continue
+		}
+
+		data, err := r.Decompress(b)
+		if err != nil {
+			return err
+		}
+		if resolved != nil {
+			if data, err = reencode(resolved, w.Schema(), data, b.Count); err != nil {
+				return fmt.Errorf("block at offset %d: %w", b.Offset, err)
+			}
+		}
+		if err = w.WriteRecords(b.Count, data); err != nil {
+			return err
+		}
+	}
+}
+
+// reencode decodes count records from data with the resolved schema and
+// encodes them with the reader schema.
+func reencode(resolved, schema avro.Schema, data []byte, count int64) ([]byte, error) {
+	dec := avro.NewDecoderForSchema(resolved, bytes.NewReader(data))
+	buf := &bytes.Buffer{}
+	enc := avro.NewEncoderForSchema(schema, buf)
+	for range count {
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		if err := enc.Encode(v); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
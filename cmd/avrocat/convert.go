@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+)
+
+// metaFlag is a repeatable key=value flag.
+type metaFlag map[string][]byte
+
+func (m metaFlag) String() string {
+	return ""
+}
+
+func (m metaFlag) Set(s string) error {
+	key, val, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", s)
+	}
+	m[key] = []byte(val)
+	return nil
+}
+
+func runFromJSON(flgs *flag.FlagSet, args []string, stdout, stderr io.Writer) int {
+	var (
+		schemaPath  string
+		codec       string
+		blockLength int
+	)
+	meta := metaFlag{}
+	flgs.StringVar(&schemaPath, "schema", "", "The schema file of the records. Required.")
+	flgs.StringVar(&codec, "codec", string(ocf.Null), "The compression codec: null, deflate, snappy or zstandard (zstd).")
+	flgs.IntVar(&blockLength, "block-length", 100, "The maximum number of records in a block.")
+	flgs.Var(meta, "meta", "A metadata key=value pair to add to the file. Can be repeated.")
+	if !parseArgs(flgs, args, 2, stderr) {
+		return 1
+	}
+	if schemaPath == "" {
+		_, _ = fmt.Fprintln(stderr, "Error: the schema is required")
+		flgs.Usage()
+		return 1
+	}
+
+	schema, err := avro.ParseFiles(schemaPath)
+	if err != nil {
+		return printError(stderr, err)
+	}
+
+	in, err := openInput(flgs.Arg(0))
+	if err != nil {
+		return printError(stderr, err)
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(flgs.Arg(1))
+	if err != nil {
+		return printError(stderr, err)
+	}
+	defer func() { _ = out.Close() }()
+
+	enc, err := ocf.NewEncoderWithSchema(schema, out,
+		ocf.WithCodec(codecFlag(codec)),
+		ocf.WithBlockLength(blockLength),
+		ocf.WithMetadata(meta),
+	)
+	if err != nil {
+		return printError(stderr, err)
+	}
+
+	dec := json.NewDecoder(bufio.NewReader(in))
+	dec.UseNumber()
+	for line := 1; ; line++ {
+		var v any
+		if err = dec.Decode(&v); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return printError(stderr, fmt.Errorf("record %d: %w", line, err))
+		}
+
+		val, err := fromJSON(schema, v)
+		if err != nil {
+			return printError(stderr, fmt.Errorf("record %d: %w", line, err))
+		}
+		if err = enc.Encode(val); err != nil {
+			return printError(stderr, fmt.Errorf("record %d: %w", line, err))
+		}
+	}
+
+	if err = enc.Close(); err != nil {
+		return printError(stderr, err)
+	}
+	if err = out.Close(); err != nil {
+		return printError(stderr, err)
+	}
+	return 0
+}
+
+func runToJSON(flgs *flag.FlagSet, args []string, stdout, stderr io.Writer) int {
+	if !parseArgs(flgs, args, 1, stderr) {
+		return 1
+	}
+
+	return withDecoder(flgs.Arg(0), stderr, func(dec *ocf.Decoder) error {
+		return writeJSON(stdout, dec, -1)
+	})
+}
+
+// writeJSON writes up to n records of dec as JSON lines to w.
+// All records are written if n is negative.
+func writeJSON(w io.Writer, dec *ocf.Decoder, n int) error {
+	bw := bufio.NewWriter(w)
+	schema := dec.Schema()
+	for i := 0; (n < 0 || i < n) && dec.HasNext(); i++ {
+		v, err := dec.DecodeGeneric()
+		if err != nil {
+			return err
+		}
+		val, err := toJSON(schema, v)
+		if err != nil {
+			return fmt.Errorf("record %d: %w", i+1, err)
+		}
+		b, err := marshalJSON(val)
+		if err != nil {
+			return err
+		}
+		_, _ = bw.Write(b)
+		_ = bw.WriteByte('\n')
+	}
+	if err := dec.Error(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// openInput opens the file at path, or stdin if path is "-".
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path) //nolint:gosec // The path is provided by the user.
+}
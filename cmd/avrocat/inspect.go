@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/hamba/avro/v2/ocf"
+)
+
+func runGetSchema(flgs *flag.FlagSet, args []string, stdout, stderr io.Writer) int {
+	if !parseArgs(flgs, args, 1, stderr) {
+		return 1
+	}
+
+	return withDecoder(flgs.Arg(0), stderr, func(dec *ocf.Decoder) error {
+		_, err := fmt.Fprintln(stdout, dec.Schema().String())
+		return err
+	})
+}
+
+func runGetMeta(flgs *flag.FlagSet, args []string, stdout, stderr io.Writer) int {
+	var key string
+	flgs.StringVar(&key, "key", "", "Only print the value of the given metadata key.")
+	if !parseArgs(flgs, args, 1, stderr) {
+		return 1
+	}
+
+	return withDecoder(flgs.Arg(0), stderr, func(dec *ocf.Decoder) error {
+		meta := dec.Metadata()
+		if key != "" {
+			val, ok := meta[key]
+			if !ok {
+				return fmt.Errorf("metadata key %q not found", key)
+			}
+			_, err := fmt.Fprintln(stdout, string(val))
+			return err
+		}
+
+		keys := make([]string, 0, len(meta))
+		for k := range meta {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if _, err := fmt.Fprintf(stdout, "%s\t%s\n", k, meta[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func runCount(flgs *flag.FlagSet, args []string, stdout, stderr io.Writer) int {
+	if !parseArgs(flgs, args, 1, stderr) {
+		return 1
+	}
+
+	var count int64
+	code := withBlocks(flgs.Arg(0), stderr, func(_ *ocf.BlockReader, b *ocf.Block) error {
+		count += b.Count
+		return nil
+	})
+	if code != 0 {
+		return code
+	}
+
+	_, _ = fmt.Fprintln(stdout, count)
+	return 0
+}
+
+func runBlocks(flgs *flag.FlagSet, args []string, stdout, stderr io.Writer) int {
+	if !parseArgs(flgs, args, 1, stderr) {
+		return 1
+	}
+
+	_, _ = fmt.Fprintln(stdout, "offset\trecords\tsize\tcodec")
+	return withBlocks(flgs.Arg(0), stderr, func(_ *ocf.BlockReader, b *ocf.Block) error {
+		_, err := fmt.Fprintf(stdout, "%d\t%d\t%d\t%s\n", b.Offset, b.Count, len(b.Data), b.Codec)
+		return err
+	})
+}
+
+func runHead(flgs *flag.FlagSet, args []string, stdout, stderr io.Writer) int {
+	var n int
+	flgs.IntVar(&n, "n", 10, "The number of records to print.")
+	if !parseArgs(flgs, args, 1, stderr) {
+		return 1
+	}
+
+	return withDecoder(flgs.Arg(0), stderr, func(dec *ocf.Decoder) error {
+		return writeJSON(stdout, dec, n)
+	})
+}
+
+// withDecoder calls fn with a decoder of the file at path, returning the exit code.
+func withDecoder(path string, stderr io.Writer, fn func(dec *ocf.Decoder) error) int {
+	f, err := os.Open(path) //nolint:gosec // The path is provided by the user.
+	if err != nil {
+		return printError(stderr, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	dec, err := ocf.NewDecoder(f)
+	if err != nil {
+		return printError(stderr, err)
+	}
+	defer func() { _ = dec.Close() }()
+
+	if err = fn(dec); err != nil {
+		return printError(stderr, err)
+	}
+	return 0
+}
+
+// withBlocks calls fn with every block of the file at path, returning the exit code.
+func withBlocks(path string, stderr io.Writer, fn func(r *ocf.BlockReader, b *ocf.Block) error) int {
+	err := withBlockReader(path, func(r *ocf.BlockReader) error {
+		for {
+			b, err := r.ReadBlock()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err = fn(r, b); err != nil {
+				return err
+			}
+		}
+	})
+	if err != nil {
+		return printError(stderr, err)
+	}
+	return 0
+}
+
+// withBlockReader calls fn with a block reader of the file at path.
+func withBlockReader(path string, fn func(r *ocf.BlockReader) error) error {
+	f, err := os.Open(path) //nolint:gosec // The path is provided by the user.
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	r, err := ocf.NewBlockReader(f)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+
+	return fn(r)
+}
@@ -0,0 +1,475 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hamba/avro/v2"
+)
+
+// toJSON converts a generic Avro value, as returned by avro.Reader.ReadNext,
+// to a value marshaled as Avro JSON.
+//
+// Bytes and fixed values are strings of code points 0-255, logical types are
+// their underlying type and non-null union values are wrapped in an object
+// keyed by their type name.
+func toJSON(schema avro.Schema, v any) (any, error) {
+	if ref, ok := schema.(*avro.RefSchema); ok {
+		schema = ref.Schema()
+	}
+
+	switch schema.Type() {
+	case avro.Record:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, typeError(schema, v)
+		}
+		fields := schema.(*avro.RecordSchema).Fields()
+		obj := make(jsonObject, 0, len(fields))
+		for _, field := range fields {
+			val, err := toJSON(field.Type(), m[field.Name()])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field.Name(), err)
+			}
+			obj = append(obj, jsonField{Name: field.Name(), Value: val})
+		}
+		return obj, nil
+
+	case avro.Array:
+		arr, ok := v.([]any)
+		if !ok {
+			return nil, typeError(schema, v)
+		}
+		items := schema.(*avro.ArraySchema).Items()
+		out := make([]any, len(arr))
+		for i, elem := range arr {
+			val, err := toJSON(items, elem)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = val
+		}
+		return out, nil
+
+	case avro.Map:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, typeError(schema, v)
+		}
+		values := schema.(*avro.MapSchema).Values()
+		out := make(map[string]any, len(m))
+		for k, elem := range m {
+			val, err := toJSON(values, elem)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = val
+		}
+		return out, nil
+
+	case avro.Union:
+		if v == nil {
+			return nil, nil
+		}
+		m, ok := v.(map[string]any)
+		if !ok || len(m) != 1 {
+			return nil, typeError(schema, v)
+		}
+		for k, elem := range m {
+			typ, _ := schema.(*avro.UnionSchema).Types().Get(k)
+			if typ == nil {
+				return nil, fmt.Errorf("unknown union type %s", k)
+			}
+			val, err := toJSON(typ, elem)
+			if err != nil {
+				return nil, err
+			}
+			return jsonObject{{Name: jsonTypeName(typ), Value: val}}, nil
+		}
+	}
+
+	return primitiveToJSON(schema, v)
+}
+
+func primitiveToJSON(schema avro.Schema, v any) (any, error) {
+	var logical avro.LogicalType
+	if ls, ok := schema.(avro.LogicalTypeSchema); ok && ls.Logical() != nil {
+		logical = ls.Logical().Type()
+	}
+
+	switch val := v.(type) {
+	case time.Time:
+		switch logical {
+		case avro.Date:
+			return val.Unix() / int64(24*time.Hour/time.Second), nil
+		case avro.TimestampMillis:
+			return val.UnixMilli(), nil
+		case avro.TimestampMicros:
+			return val.UnixMicro(), nil
+		}
+	case time.Duration:
+		switch logical {
+		case avro.TimeMillis:
+			return val.Milliseconds(), nil
+		case avro.TimeMicros:
+			return val.Microseconds(), nil
+		}
+	case *big.Rat:
+		dec, ok := schema.(avro.LogicalTypeSchema).Logical().(*avro.DecimalLogicalSchema)
+		if !ok {
+			break
+		}
+		size := 0
+		if fixed, ok := schema.(*avro.FixedSchema); ok {
+			size = fixed.Size()
+		}
+		return bytesToJSON(ratToBytes(val, dec.Scale(), size)), nil
+	case []byte:
+		return bytesToJSON(val), nil
+	case float32:
+		return floatToJSON(float64(val)), nil
+	case float64:
+		return floatToJSON(val), nil
+	case nil, bool, int, int64, string:
+		return v, nil
+	}
+
+	// Fixed values are byte arrays.
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		return bytesToJSON(b), nil
+	}
+	return nil, typeError(schema, v)
+}
+
+// fromJSON converts a value unmarshaled from Avro JSON, with numbers as
+// json.Number, to a generic value accepted by the encoder of schema.
+func fromJSON(schema avro.Schema, v any) (any, error) {
+	if ref, ok := schema.(*avro.RefSchema); ok {
+		schema = ref.Schema()
+	}
+
+	switch schema.Type() {
+	case avro.Null:
+		if v != nil {
+			return nil, typeError(schema, v)
+		}
+		return nil, nil
+
+	case avro.Boolean:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, typeError(schema, v)
+		}
+		return b, nil
+
+	case avro.Int:
+		i, err := jsonInt(schema, v, math.MinInt32, math.MaxInt32)
+		return int32(i), err
+
+	case avro.Long:
+		return jsonInt(schema, v, math.MinInt64, math.MaxInt64)
+
+	case avro.Float:
+		f, err := jsonFloat(schema, v)
+		return float32(f), err
+
+	case avro.Double:
+		return jsonFloat(schema, v)
+
+	case avro.String:
+		s, ok := v.(string)
+		if !ok {
+			return nil, typeError(schema, v)
+		}
+		return s, nil
+
+	case avro.Bytes:
+		return jsonBytes(schema, v)
+
+	case avro.Fixed:
+		b, err := jsonBytes(schema, v)
+		if err != nil {
+			return nil, err
+		}
+		size := schema.(*avro.FixedSchema).Size()
+		if len(b) != size {
+			return nil, fmt.Errorf("expected %d bytes for %s, got %d", size, jsonTypeName(schema), len(b))
+		}
+		arr := reflect.New(reflect.ArrayOf(size, reflect.TypeFor[byte]())).Elem()
+		reflect.Copy(arr, reflect.ValueOf(b))
+		return arr.Interface(), nil
+
+	case avro.Enum:
+		s, ok := v.(string)
+		if !ok {
+			return nil, typeError(schema, v)
+		}
+		if !slices.Contains(schema.(*avro.EnumSchema).Symbols(), s) {
+			return nil, fmt.Errorf("unknown symbol %q of %s", s, jsonTypeName(schema))
+		}
+		return s, nil
+
+	case avro.Array:
+		arr, ok := v.([]any)
+		if !ok {
+			return nil, typeError(schema, v)
+		}
+		items := schema.(*avro.ArraySchema).Items()
+		out := make([]any, len(arr))
+		for i, elem := range arr {
+			val, err := fromJSON(items, elem)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = val
+		}
+		return out, nil
+
+	case avro.Map:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, typeError(schema, v)
+		}
+		values := schema.(*avro.MapSchema).Values()
+		out := make(map[string]any, len(m))
+		for k, elem := range m {
+			val, err := fromJSON(values, elem)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = val
+		}
+		return out, nil
+
+	case avro.Record:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, typeError(schema, v)
+		}
+		out := make(map[string]any, len(m))
+		for _, field := range schema.(*avro.RecordSchema).Fields() {
+			elem, ok := m[field.Name()]
+			if !ok {
+				if !field.HasDefault() {
+					return nil, fmt.Errorf("missing required field %s", field.Name())
+				}
+				if field.Default() != nil {
+					// The encoder writes the default value.
+					continue
+				}
+			}
+			val, err := fromJSON(field.Type(), elem)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field.Name(), err)
+			}
+			out[field.Name()] = val
+		}
+		return out, nil
+
+	case avro.Union:
+		return unionFromJSON(schema.(*avro.UnionSchema), v)
+	}
+
+	return nil, fmt.Errorf("unsupported schema type %s", schema.Type())
+}
+
+func unionFromJSON(schema *avro.UnionSchema, v any) (any, error) {
+	if v == nil {
+		if _, pos := schema.Types().Get(string(avro.Null)); pos < 0 {
+			return nil, typeError(schema, v)
+		}
+		return map[string]any{}, nil
+	}
+
+	m, ok := v.(map[string]any)
+	if !ok || len(m) != 1 {
+		return nil, fmt.Errorf("expected union value wrapped in an object with its type name, got %v", v)
+	}
+	for k, elem := range m {
+		for _, typ := range schema.Types() {
+			if jsonTypeName(typ) != k {
+				continue
+			}
+			val, err := fromJSON(typ, elem)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{unionKey(typ): val}, nil
+		}
+		return nil, fmt.Errorf("unknown union type %s", k)
+	}
+	return nil, nil
+}
+
+func jsonInt(schema avro.Schema, v any, lower, upper int64) (int64, error) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, typeError(schema, v)
+	}
+	i, err := n.Int64()
+	if err != nil || i < lower || i > upper {
+		return 0, fmt.Errorf("invalid %s %s", schema.Type(), n)
+	}
+	return i, nil
+}
+
+func jsonFloat(schema avro.Schema, v any) (float64, error) {
+	switch val := v.(type) {
+	case json.Number:
+		return val.Float64()
+	case string:
+		switch val {
+		case "NaN":
+			return math.NaN(), nil
+		case "Infinity":
+			return math.Inf(1), nil
+		case "-Infinity":
+			return math.Inf(-1), nil
+		}
+	}
+	return 0, typeError(schema, v)
+}
+
+func jsonBytes(schema avro.Schema, v any) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, typeError(schema, v)
+	}
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 255 {
+			return nil, fmt.Errorf("invalid %s, code point %U is out of range", schema.Type(), r)
+		}
+		b = append(b, byte(r))
+	}
+	return b, nil
+}
+
+func bytesToJSON(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		sb.WriteRune(rune(c))
+	}
+	return sb.String()
+}
+
+func floatToJSON(f float64) any {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return f
+}
+
+// ratToBytes returns the big-endian two's complement of the unscaled value of r.
+// If size is positive, the bytes are sign extended to size.
+func ratToBytes(r *big.Rat, scale, size int) []byte {
+	unscaled := new(big.Int).Mul(r.Num(), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil))
+	unscaled.Quo(unscaled, r.Denom())
+
+	n := len(unscaled.Bytes()) + 1
+	if size > n {
+		n = size
+	}
+	if unscaled.Sign() < 0 {
+		// Two's complement of a negative number is 2^(8n) + x.
+		unscaled.Add(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(8*n)))
+	}
+	b := unscaled.FillBytes(make([]byte, n))
+	if size > 0 {
+		return b[len(b)-size:]
+	}
+
+	// Strip redundant sign bytes.
+	for len(b) > 1 && (b[0] == 0 && b[1]&0x80 == 0 || b[0] == 0xff && b[1]&0x80 != 0) {
+		b = b[1:]
+	}
+	return b
+}
+
+// jsonTypeName returns the name of schema in Avro JSON union wrapping.
+func jsonTypeName(schema avro.Schema) string {
+	if ref, ok := schema.(*avro.RefSchema); ok {
+		schema = ref.Schema()
+	}
+	if n, ok := schema.(avro.NamedSchema); ok {
+		return n.FullName()
+	}
+	return string(schema.Type())
+}
+
+// unionKey returns the name the encoder resolves schema by in a union.
+func unionKey(schema avro.Schema) string {
+	name := jsonTypeName(schema)
+	if _, ok := schema.(avro.NamedSchema); ok {
+		return name
+	}
+	if ls, ok := schema.(avro.LogicalTypeSchema); ok && ls.Logical() != nil {
+		name += "." + string(ls.Logical().Type())
+	}
+	return name
+}
+
+func typeError(schema avro.Schema, v any) error {
+	if v == nil {
+		return fmt.Errorf("unexpected null for %s", jsonTypeName(schema))
+	}
+	return fmt.Errorf("unexpected %T for %s", v, jsonTypeName(schema))
+}
+
+// jsonObject is a JSON object that keeps the order of its fields.
+type jsonObject []jsonField
+
+type jsonField struct {
+	Name  string
+	Value any
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(field.Name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+
+		val, err := marshalJSON(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalJSON marshals v without escaping HTML characters.
+func marshalJSON(v any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+type command struct {
+	usage string
+	help  string
+	run   func(flgs *flag.FlagSet, args []string, stdout, stderr io.Writer) int
+}
+
+var commands = map[string]command{
+	"getschema": {usage: "getschema file", help: "Print the schema of a container file.", run: runGetSchema},
+	"getmeta":   {usage: "getmeta [options] file", help: "Print the metadata of a container file.", run: runGetMeta},
+	"count":     {usage: "count file", help: "Count the records of a container file, without decoding them.", run: runCount},
+	"blocks":    {usage: "blocks file", help: "List the blocks of a container file.", run: runBlocks},
+	"head":      {usage: "head [options] file", help: "Print the first records of a container file as JSON.", run: runHead},
+	"tojson":    {usage: "tojson file", help: "Print the records of a container file as JSON lines.", run: runToJSON},
+	"concat": {
+		usage: "concat [options] out.avro in.avro...",
+		help:  "Concatenate container files, copying blocks without decoding them where possible.",
+		run:   runConcat,
+	},
+	"recodec": {
+		usage: "recodec -codec name in.avro out.avro",
+		help:  "Change the compression codec of a container file.",
+		run:   runRecodec,
+	},
+	"random": {
+		usage: "random -schema file [options] out",
+		help:  "Write random records of a schema, to stdout if out is -.",
+		run:   runRandom,
+	},
+	"fromjson": {
+		usage: "fromjson -schema file [options] in.jsonl out.avro",
+		help:  "Write JSON lines to a container file, reading stdin if in.jsonl is -.",
+		run:   runFromJSON,
+	},
+}
+
+func main() {
+	os.Exit(realMain(os.Args, os.Stdout, os.Stderr))
+}
+
+func realMain(args []string, stdout, stderr io.Writer) int {
+	if len(args) < 2 {
+		usage(stderr)
+		return 1
+	}
+
+	cmd, ok := commands[args[1]]
+	if !ok {
+		if args[1] != "-h" && args[1] != "help" {
+			_, _ = fmt.Fprintf(stderr, "Error: unknown command %q\n", args[1])
+		}
+		usage(stderr)
+		return 1
+	}
+
+	return cmd.run(newFlagSet(args[1], cmd, stderr), args[1:], stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	_, _ = fmt.Fprintln(w, "Usage: avrocat command [options] args")
+	_, _ = fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "  %-12s %s\n", name, commands[name].help)
+	}
+	_, _ = fmt.Fprintln(w, "\nCheck the options of a command with: avrocat command -h")
+}
+
+// newFlagSet returns a flag set for a command.
+func newFlagSet(name string, cmd command, stderr io.Writer) *flag.FlagSet {
+	flgs := flag.NewFlagSet(name, flag.ContinueOnError)
+	flgs.SetOutput(stderr)
+	flgs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "Usage: avrocat %s\n", cmd.usage)
+		_, _ = fmt.Fprintln(stderr, cmd.help)
+		hasFlags := false
+		flgs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			_, _ = fmt.Fprintln(stderr, "Options:")
+			flgs.PrintDefaults()
+		}
+	}
+	return flgs
+}
+
+// parseArgs parses the command arguments, requiring n positional arguments.
+func parseArgs(flgs *flag.FlagSet, args []string, n int, stderr io.Writer) bool {
+	if err := flgs.Parse(args[1:]); err != nil {
+		return false
+	}
+	if flgs.NArg() != n {
+		_, _ = fmt.Fprintf(stderr, "Error: expected %d arguments, got %d\n", n, flgs.NArg())
+		flgs.Usage()
+		return false
+	}
+	return true
+}
+
+func printError(stderr io.Writer, err error) int {
+	_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
+	return 2
+}
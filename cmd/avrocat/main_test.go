@@ -0,0 +1,459 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+	"type": "record",
+	"name": "test",
+	"fields": [
+		{"name": "a", "type": "long"},
+		{"name": "b", "type": ["null", "string"]},
+		{"name": "c", "type": "bytes"}
+	]
+}`
+
+type testRecord struct {
+	A int64   `avro:"a"`
+	B *string `avro:"b"`
+	C []byte  `avro:"c"`
+}
+
+func writeTestFile(t *testing.T, n int, opts ...ocf.EncoderFunc) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.avro")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	enc, err := ocf.NewEncoder(testSchema, f, opts...)
+	require.NoError(t, err)
+	str := "foo"
+	for i := range n {
+		require.NoError(t, enc.Encode(testRecord{A: int64(i), B: &str, C: []byte{0xff}}))
+	}
+	require.NoError(t, enc.Close())
+	return path
+}
+
+func run(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := realMain(append([]string{"avrocat"}, args...), stdout, stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestAvroCat_RequiredArgs(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantExitCode int
+	}{
+		{
+			name:         "validates a command is set",
+			args:         []string{"avrocat"},
+			wantExitCode: 1,
+		},
+		{
+			name:         "validates the command exists",
+			args:         []string{"avrocat", "unknown"},
+			wantExitCode: 1,
+		},
+		{
+			name:         "validates a file is set",
+			args:         []string{"avrocat", "count"},
+			wantExitCode: 1,
+		},
+		{
+			name:         "validates the file exists",
+			args:         []string{"avrocat", "count", "some/file"},
+			wantExitCode: 2,
+		},
+		{
+			name:         "validates the flags",
+			args:         []string{"avrocat", "head", "-n", "foo", "some/file"},
+			wantExitCode: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := realMain(test.args, io.Discard, io.Discard)
+
+			assert.Equal(t, test.wantExitCode, got)
+		})
+	}
+}
+
+func TestAvroCat_GetSchema(t *testing.T) {
+	path := writeTestFile(t, 1)
+
+	code, stdout, _ := run(t, "getschema", path)
+
+	require.Equal(t, 0, code)
+	assert.JSONEq(t, testSchema, stdout)
+}
+
+func TestAvroCat_GetMeta(t *testing.T) {
+	path := writeTestFile(t, 1, ocf.WithCodec(ocf.Snappy), ocf.WithMetadataKeyVal("foo", []byte("bar")))
+
+	code, stdout, _ := run(t, "getmeta", path)
+
+	require.Equal(t, 0, code)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "avro.codec\tsnappy", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "avro.schema\t"))
+	assert.Equal(t, "foo\tbar", lines[2])
+}
+
+func TestAvroCat_GetMetaKey(t *testing.T) {
+	path := writeTestFile(t, 1, ocf.WithMetadataKeyVal("foo", []byte("bar")))
+
+	code, stdout, _ := run(t, "getmeta", "-key", "foo", path)
+	require.Equal(t, 0, code)
+	assert.Equal(t, "bar\n", stdout)
+
+	code, _, stderr := run(t, "getmeta", "-key", "baz", path)
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `"baz" not found`)
+}
+
+func TestAvroCat_Count(t *testing.T) {
+	path := writeTestFile(t, 250, ocf.WithBlockLength(100))
+
+	code, stdout, _ := run(t, "count", path)
+
+	require.Equal(t, 0, code)
+	assert.Equal(t, "250\n", stdout)
+}
+
+func TestAvroCat_Blocks(t *testing.T) {
+	path := writeTestFile(t, 250, ocf.WithBlockLength(100), ocf.WithCodec(ocf.Deflate))
+
+	code, stdout, _ := run(t, "blocks", path)
+
+	require.Equal(t, 0, code)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "offset\trecords\tsize\tcodec", lines[0])
+	for i, records := range []string{"100", "100", "50"} {
+		fields := strings.Split(lines[i+1], "\t")
+		require.Len(t, fields, 4)
+		assert.Equal(t, records, fields[1])
+		assert.Equal(t, "deflate", fields[3])
+	}
+}
+
+func TestAvroCat_Head(t *testing.T) {
+	path := writeTestFile(t, 20)
+
+	code, stdout, _ := run(t, "head", "-n", "2", path)
+
+	require.Equal(t, 0, code)
+	assert.Equal(t, `{"a":0,"b":{"string":"foo"},"c":"ÿ"}`+"\n"+`{"a":1,"b":{"string":"foo"},"c":"ÿ"}`+"\n", stdout)
+}
+
+func TestAvroCat_HeadDefault(t *testing.T) {
+	path := writeTestFile(t, 20)
+
+	code, stdout, _ := run(t, "head", path)
+
+	require.Equal(t, 0, code)
+	assert.Len(t, strings.Split(strings.TrimSpace(stdout), "\n"), 10)
+}
+
+const testJSONSchema = `{
+	"type": "record",
+	"name": "org.hamba.avro.test",
+	"fields": [
+		{"name": "a", "type": "int"},
+		{"name": "c", "type": {"type": "enum", "name": "enum", "symbols": ["A", "B"]}},
+		{"name": "b", "type": ["null", "string", "org.hamba.avro.enum"], "default": null},
+		{"name": "d", "type": {"type": "fixed", "name": "fixed", "size": 2}},
+		{"name": "e", "type": {"type": "array", "items": "double"}},
+		{"name": "f", "type": {"type": "map", "values": ["null", "long"]}},
+		{"name": "g", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "h", "type": {"type": "bytes", "logicalType": "decimal", "precision": 4, "scale": 2}},
+		{"name": "i", "type": "string", "default": "foo"}
+	]
+}`
+
+func writeJSONFile(t *testing.T, data string) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "schema.avsc")
+	require.NoError(t, os.WriteFile(schemaPath, []byte(testJSONSchema), 0o600))
+	inPath := filepath.Join(dir, "in.jsonl")
+	require.NoError(t, os.WriteFile(inPath, []byte(data), 0o600))
+	return schemaPath, inPath
+}
+
+func TestAvroCat_FromJSONToJSON(t *testing.T) {
+	in := `{"a":1,"c":"A","b":{"string":"bar"},"d":"\u0000ÿ","e":[1.5,"NaN"],"f":{"x":{"long":2},"y":null},"g":1700000000123,"h":"\u0004Ò","i":"baz"}
+{"a":-2,"c":"B","b":{"org.hamba.avro.enum":"B"},"d":"ab","e":[],"f":{},"g":0,"h":"ÿ"}
+{"a":3,"c":"A","d":"cd","e":[],"f":{},"g":0,"h":"\u0000"}
+`
+	schemaPath, inPath := writeJSONFile(t, in)
+	outPath := filepath.Join(t.TempDir(), "out.avro")
+
+	code, _, stderr := run(t, "fromjson", "-schema", schemaPath, inPath, outPath)
+	require.Equal(t, 0, code, stderr)
+
+	code, stdout, stderr := run(t, "tojson", outPath)
+	require.Equal(t, 0, code, stderr)
+	want := `{"a":1,"c":"A","b":{"string":"bar"},"d":"\u0000ÿ","e":[1.5,"NaN"],"f":{"x":{"long":2},"y":null},"g":1700000000123,"h":"\u0004Ò","i":"baz"}
+{"a":-2,"c":"B","b":{"org.hamba.avro.enum":"B"},"d":"ab","e":[],"f":{},"g":0,"h":"ÿ","i":"foo"}
+{"a":3,"c":"A","b":null,"d":"cd","e":[],"f":{},"g":0,"h":"\u0000","i":"foo"}
+`
+	assert.Equal(t, want, stdout)
+}
+
+func TestAvroCat_FromJSONOptions(t *testing.T) {
+	in := strings.Repeat(`{"a":1,"c":"A","d":"ab","e":[],"f":{},"g":0,"h":"\u0000"}`+"\n", 5)
+	schemaPath, inPath := writeJSONFile(t, in)
+	outPath := filepath.Join(t.TempDir(), "out.avro")
+
+	code, _, stderr := run(t, "fromjson", "-schema", schemaPath, "-codec", "zstd", "-block-length", "2",
+		"-meta", "foo=bar", "-meta", "baz=qux", inPath, outPath)
+	require.Equal(t, 0, code, stderr)
+
+	code, stdout, _ := run(t, "blocks", outPath)
+	require.Equal(t, 0, code)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 4)
+	assert.True(t, strings.HasSuffix(lines[1], "\tzstandard"))
+
+	code, stdout, _ = run(t, "getmeta", "-key", "foo", outPath)
+	require.Equal(t, 0, code)
+	assert.Equal(t, "bar\n", stdout)
+	code, stdout, _ = run(t, "getmeta", "-key", "baz", outPath)
+	require.Equal(t, 0, code)
+	assert.Equal(t, "qux\n", stdout)
+}
+
+func TestAvroCat_FromJSONErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr string
+	}{
+		{
+			name:    "unwrapped union",
+			in:      `{"a":1,"b":"bar","c":"A","d":"ab","e":[],"f":{},"g":0,"h":""}`,
+			wantErr: "record 1: b: expected union value wrapped",
+		},
+		{
+			name:    "unknown union type",
+			in:      `{"a":1,"b":{"int":1},"c":"A","d":"ab","e":[],"f":{},"g":0,"h":""}`,
+			wantErr: "record 1: b: unknown union type int",
+		},
+		{
+			name:    "int out of range",
+			in:      `{"a":2147483648,"c":"A","d":"ab","e":[],"f":{},"g":0,"h":""}`,
+			wantErr: "record 1: a: invalid int",
+		},
+		{
+			name:    "unknown symbol",
+			in:      `{"a":1,"c":"C","d":"ab","e":[],"f":{},"g":0,"h":""}`,
+			wantErr: `record 1: c: unknown symbol "C"`,
+		},
+		{
+			name:    "wrong fixed size",
+			in:      `{"a":1,"c":"A","d":"abc","e":[],"f":{},"g":0,"h":""}`,
+			wantErr: "record 1: d: expected 2 bytes",
+		},
+		{
+			name:    "missing field",
+			in:      `{"a":1,"d":"ab","e":[],"f":{},"g":0,"h":""}`,
+			wantErr: "record 1: missing required field c",
+		},
+		{
+			name:    "invalid json",
+			in:      `{"a":`,
+			wantErr: "record 1: unexpected EOF",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schemaPath, inPath := writeJSONFile(t, test.in)
+			outPath := filepath.Join(t.TempDir(), "out.avro")
+
+			code, _, stderr := run(t, "fromjson", "-schema", schemaPath, inPath, outPath)
+
+			assert.Equal(t, 2, code)
+			assert.Contains(t, stderr, test.wantErr)
+		})
+	}
+}
+
+func TestAvroCat_FromJSONRequiresSchema(t *testing.T) {
+	code, _, stderr := run(t, "fromjson", "in.jsonl", "out.avro")
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "the schema is required")
+}
+
+func TestAvroCat_Concat(t *testing.T) {
+	in1 := writeTestFile(t, 150, ocf.WithBlockLength(100), ocf.WithCodec(ocf.Deflate), ocf.WithMetadataKeyVal("foo", []byte("bar")))
+	in2 := writeTestFile(t, 50, ocf.WithCodec(ocf.Snappy))
+	out := filepath.Join(t.TempDir(), "out.avro")
+
+	code, _, stderr := run(t, "concat", out, in1, in2)
+	require.Equal(t, 0, code, stderr)
+
+	code, stdout, _ := run(t, "blocks", out)
+	require.Equal(t, 0, code)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 4)
+	for i, records := range []string{"100", "50", "50"} {
+		fields := strings.Split(lines[i+1], "\t")
+		assert.Equal(t, records, fields[1])
+		assert.Equal(t, "deflate", fields[3])
+	}
+
+	code, stdout, _ = run(t, "getmeta", "-key", "foo", out)
+	require.Equal(t, 0, code)
+	assert.Equal(t, "bar\n", stdout)
+
+	code, stdout, _ = run(t, "tojson", out)
+	require.Equal(t, 0, code)
+	lines = strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 200)
+	assert.Equal(t, `{"a":149,"b":{"string":"foo"},"c":"ÿ"}`, lines[149])
+	assert.Equal(t, `{"a":0,"b":{"string":"foo"},"c":"ÿ"}`, lines[150])
+}
+
+func TestAvroCat_ConcatCopiesBlocks(t *testing.T) {
+	in := writeTestFile(t, 150, ocf.WithBlockLength(100), ocf.WithCodec(ocf.Deflate))
+	out := filepath.Join(t.TempDir(), "out.avro")
+
+	code, _, stderr := run(t, "concat", out, in)
+	require.Equal(t, 0, code, stderr)
+
+	_, want, _ := run(t, "blocks", in)
+	_, got, _ := run(t, "blocks", out)
+	assert.Equal(t, want, got)
+}
+
+func TestAvroCat_ConcatSchemaMismatch(t *testing.T) {
+	in1 := writeTestFile(t, 10)
+
+	schema := `{"type":"record","name":"test","fields":[{"name":"a","type":"long"},{"name":"d","type":"string"}]}`
+	in2 := filepath.Join(t.TempDir(), "other.avro")
+	f, err := os.Create(in2)
+	require.NoError(t, err)
+	enc, err := ocf.NewEncoder(schema, f)
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(map[string]any{"a": int64(42), "d": "bar"}))
+	require.NoError(t, enc.Close())
+	require.NoError(t, f.Close())
+
+	out := filepath.Join(t.TempDir(), "out.avro")
+	code, _, stderr := run(t, "concat", out, in1, in2)
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "schema differs from the output schema")
+
+	readerSchema := filepath.Join(t.TempDir(), "reader.avsc")
+	require.NoError(t, os.WriteFile(readerSchema, []byte(`{
+		"type": "record",
+		"name": "test",
+		"fields": [
+			{"name": "a", "type": "long"},
+			{"name": "b", "type": ["null", "string"], "default": null}
+		]
+	}`), 0o600))
+
+	code, _, stderr = run(t, "concat", "-reader-schema", readerSchema, out, in1, in2)
+	require.Equal(t, 0, code, stderr)
+
+	code, stdout, _ := run(t, "tojson", out)
+	require.Equal(t, 0, code)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 11)
+	assert.Equal(t, `{"a":9,"b":{"string":"foo"}}`, lines[9])
+	assert.Equal(t, `{"a":42,"b":null}`, lines[10])
+}
+
+func TestAvroCat_Recodec(t *testing.T) {
+	in := writeTestFile(t, 150, ocf.WithBlockLength(100), ocf.WithCodec(ocf.Deflate))
+	out := filepath.Join(t.TempDir(), "out.avro")
+
+	code, _, stderr := run(t, "recodec", "-codec", "zstd", in, out)
+	require.Equal(t, 0, code, stderr)
+
+	code, stdout, _ := run(t, "getmeta", "-key", "avro.codec", out)
+	require.Equal(t, 0, code)
+	assert.Equal(t, "zstandard\n", stdout)
+
+	_, want, _ := run(t, "tojson", in)
+	_, got, _ := run(t, "tojson", out)
+	assert.Equal(t, want, got)
+}
+
+func TestAvroCat_RecodecRequiresCodec(t *testing.T) {
+	code, _, stderr := run(t, "recodec", "in.avro", "out.avro")
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "the codec is required")
+}
+
+func TestAvroCat_Random(t *testing.T) {
+	schemaPath, _ := writeJSONFile(t, "")
+	out := filepath.Join(t.TempDir(), "out.avro")
+
+	code, _, stderr := run(t, "random", "-schema", schemaPath, "-n", "25", "-codec", "deflate", out)
+	require.Equal(t, 0, code, stderr)
+
+	code, stdout, _ := run(t, "count", out)
+	require.Equal(t, 0, code)
+	assert.Equal(t, "25\n", stdout)
+
+	code, stdout, _ = run(t, "getmeta", "-key", "avro.codec", out)
+	require.Equal(t, 0, code)
+	assert.Equal(t, "deflate\n", stdout)
+}
+
+func TestAvroCat_RandomJSON(t *testing.T) {
+	schemaPath, _ := writeJSONFile(t, "")
+
+	code, stdout1, stderr := run(t, "random", "-schema", schemaPath, "-n", "5", "-seed", "7", "-format", "json", "-")
+	require.Equal(t, 0, code, stderr)
+	_, stdout2, _ := run(t, "random", "-schema", schemaPath, "-n", "5", "-seed", "7", "-format", "json", "-")
+	_, stdout3, _ := run(t, "random", "-schema", schemaPath, "-n", "5", "-seed", "8", "-format", "json", "-")
+
+	assert.Len(t, strings.Split(strings.TrimSpace(stdout1), "\n"), 5)
+	assert.Equal(t, stdout1, stdout2)
+	assert.NotEqual(t, stdout1, stdout3)
+
+	// The generated records are valid input to fromjson.
+	_, inPath := writeJSONFile(t, stdout1)
+	out := filepath.Join(t.TempDir(), "out.avro")
+	code, _, stderr = run(t, "fromjson", "-schema", schemaPath, inPath, out)
+	require.Equal(t, 0, code, stderr)
+	_, stdout, _ := run(t, "tojson", out)
+	assert.Equal(t, stdout1, stdout)
+}
+
+func TestAvroCat_RandomUnknownFormat(t *testing.T) {
+	schemaPath, _ := writeJSONFile(t, "")
+
+	code, _, stderr := run(t, "random", "-schema", schemaPath, "-format", "xml", "-")
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `unknown format "xml"`)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+)
+
+func runRandom(flgs *flag.FlagSet, args []string, stdout, stderr io.Writer) int {
+	var (
+		schemaPath string
+		count      int
+		seed       int64
+		maxDepth   int
+		format     string
+		codec      string
+	)
+	flgs.StringVar(&schemaPath, "schema", "", "The schema file of the records. Required.")
+	flgs.IntVar(&count, "n", 10, "The number of records to generate.")
+	flgs.Int64Var(&seed, "seed", 1, "The seed of the generated records.")
+	flgs.IntVar(&maxDepth, "max-depth", 5, "The maximum depth of nested records.")
+	flgs.StringVar(&format, "format", "ocf", "The output format: ocf, binary or json.")
+	flgs.StringVar(&codec, "codec", string(ocf.Null), "The compression codec of ocf output.")
+	if !parseArgs(flgs, args, 1, stderr) {
+		return 1
+	}
+	if schemaPath == "" {
+		_, _ = fmt.Fprintln(stderr, "Error: the schema is required")
+		flgs.Usage()
+		return 1
+	}
+
+	schema, err := avro.ParseFiles(schemaPath)
+	if err != nil {
+		return printError(stderr, err)
+	}
+
+	var out io.Writer = stdout
+	if path := flgs.Arg(0); path != "-" {
+		f, err := os.Create(path) //nolint:gosec // The path is provided by the user.
+		if err != nil {
+			return printError(stderr, err)
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+
+	var (
+		write func(v any) error
+		flush func() error
+	)
+	bw := bufio.NewWriter(out)
+	switch format {
+	case "ocf":
+		enc, err := ocf.NewEncoderWithSchema(schema, bw, ocf.WithCodec(codecFlag(codec)))
+		if err != nil {
+			return printError(stderr, err)
+		}
+		write, flush = enc.Encode, enc.Close
+	case "binary":
+		enc := avro.NewEncoderForSchema(schema, bw)
+		write = enc.Encode
+	case "json":
+		write = func(v any) error {
+			val, err := toJSON(schema, v)
+			if err != nil {
+				return err
+			}
+			b, err := marshalJSON(val)
+			if err != nil {
+				return err
+			}
+			_, _ = bw.Write(b)
+			return bw.WriteByte('\n')
+		}
+	default:
+		_, _ = fmt.Fprintf(stderr, "Error: unknown format %q\n", format)
+		flgs.Usage()
+		return 1
+	}
+
+	gen := avro.NewRandomGenerator(schema, seed, avro.WithRandomMaxDepth(maxDepth))
+	for range count {
+		v, err := gen.Next()
+		if err != nil {
+			return printError(stderr, err)
+		}
+		if err = write(v); err != nil {
+			return printError(stderr, err)
+		}
+	}
+
+	if flush != nil {
+		if err = flush(); err != nil {
+			return printError(stderr, err)
+		}
+	}
+	if err = bw.Flush(); err != nil {
+		return printError(stderr, err)
+	}
+	return 0
+}
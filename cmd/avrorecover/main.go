@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hamba/avro/v2/ocf"
+)
+
+type config struct {
+	Quiet bool
+}
+
+func main() {
+	os.Exit(realMain(os.Args, os.Stdout, os.Stderr))
+}
+
+func realMain(args []string, stdout, stderr io.Writer) int {
+	var cfg config
+	flgs := flag.NewFlagSet("avrorecover", flag.ExitOnError)
+	flgs.SetOutput(stderr)
+	flgs.BoolVar(&cfg.Quiet, "q", false, "Do not report corrupted blocks.")
+	flgs.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: avrorecover [options] input output")
+		_, _ = fmt.Fprintln(stderr, "Options:")
+		flgs.PrintDefaults()
+		_, _ = fmt.Fprintln(stderr, "\nThe readable blocks of the input container file are copied to the output file.")
+	}
+	if err := flgs.Parse(args[1:]); err != nil {
+		return 1
+	}
+	if flgs.NArg() != 2 {
+		_, _ = fmt.Fprintln(stderr, "Error: an input and an output file are required")
+		return 1
+	}
+
+	in, err := os.Open(flgs.Arg(0))
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(flgs.Arg(1))
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+	defer func() { _ = out.Close() }()
+
+	var corrupted int
+	n, err := ocf.Salvage(out, in, func(err *ocf.BlockError) {
+		corrupted++
+		if !cfg.Quiet {
+			_, _ = fmt.Fprintf(stderr, "Corrupted block at offset %d: %v\n", err.Offset, err.Err)
+		}
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	_, _ = fmt.Fprintf(stdout, "Recovered %d records, skipped %d corrupted blocks\n", n, corrupted)
+	return 0
+}
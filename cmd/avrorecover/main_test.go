@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvroRecover_RequiredFlags(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantExitCode int
+	}{
+		{
+			name:         "validates no files are set",
+			args:         []string{"avrorecover"},
+			wantExitCode: 1,
+		},
+		{
+			name:         "validates output file is set",
+			args:         []string{"avrorecover", "some/file"},
+			wantExitCode: 1,
+		},
+		{
+			name:         "validates input file exists",
+			args:         []string{"avrorecover", "some/file", "some/other"},
+			wantExitCode: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := realMain(test.args, io.Discard, io.Discard)
+
+			assert.Equal(t, test.wantExitCode, got)
+		})
+	}
+}
+
+func TestAvroRecover_SalvagesFile(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.avro")
+	out := filepath.Join(dir, "out.avro")
+
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"long"`, buf, ocf.WithBlockLength(2))
+	require.NoError(t, err)
+	for i := range 5 {
+		require.NoError(t, enc.Encode(int64(i)))
+	}
+	require.NoError(t, enc.Close())
+	data := buf.Bytes()
+	require.NoError(t, os.WriteFile(in, data[:len(data)-3], 0o600))
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	got := realMain([]string{"avrorecover", in, out}, stdout, stderr)
+
+	require.Equal(t, 0, got, stderr.String())
+	assert.Equal(t, "Recovered 4 records, skipped 1 corrupted blocks\n", stdout.String())
+	assert.Contains(t, stderr.String(), "Corrupted block at offset")
+
+	f, err := os.Open(out)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	dec, err := ocf.NewDecoder(f)
+	require.NoError(t, err)
+	var n int
+	for dec.HasNext() {
+		var v int64
+		require.NoError(t, dec.Decode(&v))
+		n++
+	}
+	require.NoError(t, dec.Error())
+	assert.Equal(t, 4, n)
+}
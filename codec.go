@@ -35,6 +35,8 @@ type ValEncoder interface {
 
 // ReadVal parses Avro value and stores the result in the value pointed to by obj.
 func (r *Reader) ReadVal(schema Schema, obj any) {
+	r.startValue()
+
 	decoder := r.cfg.getDecoderFromCache(schema.CacheFingerprint(), reflect2.RTypeOf(obj))
 	if decoder == nil {
 		typ := reflect2.TypeOf(obj)
@@ -233,13 +235,27 @@ func encoderOfType(e *encoderContext, schema Schema, typ reflect2.Type) ValEncod
 	}
 }
 
+// typeError is an error decoding into an unsupported Go type, rather than
+// an error of the input.
+type typeError struct {
+	err error
+}
+
+func (e *typeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *typeError) Unwrap() error {
+	return e.err
+}
+
 type errorDecoder struct {
 	err error
 }
 
 func (d *errorDecoder) Decode(_ unsafe.Pointer, r *Reader) {
 	if r.Error == nil {
-		r.Error = d.err
+		r.Error = &typeError{err: d.err}
 	}
 }
 
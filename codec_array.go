@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strconv"
 	"unsafe"
 
 	"github.com/modern-go/reflect2"
@@ -30,15 +31,24 @@ func decoderOfArray(d *decoderContext, arr *ArraySchema, typ reflect2.Type) ValD
 	sliceType := typ.(*reflect2.UnsafeSliceType)
 	decoder := decoderOfType(d, arr.Items(), sliceType.Elem())
 
-	return &arrayDecoder{typ: sliceType, decoder: decoder}
+	return &arrayDecoder{typ: sliceType, items: arr.Items(), decoder: decoder}
 }
 
 type arrayDecoder struct {
 	typ     *reflect2.UnsafeSliceType
+	items   Schema
 	decoder ValDecoder
 }
 
 func (d *arrayDecoder) Decode(ptr unsafe.Pointer, r *Reader) {
+	if !r.enterNested() {
+		return
+	}
+	d.decode(ptr, r)
+	r.leaveNested()
+}
+
+func (d *arrayDecoder) decode(ptr unsafe.Pointer, r *Reader) {
 	var size int
 	sliceType := d.typ
 
@@ -59,14 +69,18 @@ func (d *arrayDecoder) Decode(ptr unsafe.Pointer, r *Reader) {
 			r.ReportError("decode array", "size is greater than `Config.MaxSliceAllocSize`")
 			return
 		}
+		if !r.allocate(l, int64(sliceType.Elem().Type1().Size())) {
+			return
+		}
 
 		sliceType.UnsafeGrow(ptr, size)
 
 		for i := start; i < size; i++ {
 			elemPtr := sliceType.UnsafeGetIndex(ptr, i)
+			elemStart := r.inputOffset()
 			d.decoder.Decode(elemPtr, r)
 			if r.Error != nil {
-				r.Error = fmt.Errorf("reading %s: %w", d.typ.String(), r.Error)
+				r.wrapDecodeError("["+strconv.Itoa(i)+"]", d.items, elemStart)
 				return
 			}
 		}
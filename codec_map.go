@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strconv"
 	"unsafe"
 
 	"github.com/modern-go/reflect2"
@@ -46,6 +47,7 @@ func decoderOfMap(d *decoderContext, m *MapSchema, typ reflect2.Type) ValDecoder
 	return &mapDecoder{
 		mapType:  mapType,
 		elemType: mapType.Elem(),
+		values:   m.Values(),
 		decoder:  decoder,
 	}
 }
@@ -53,10 +55,19 @@ func decoderOfMap(d *decoderContext, m *MapSchema, typ reflect2.Type) ValDecoder
 type mapDecoder struct {
 	mapType  *reflect2.UnsafeMapType
 	elemType reflect2.Type
+	values   Schema
 	decoder  ValDecoder
 }
 
 func (d *mapDecoder) Decode(ptr unsafe.Pointer, r *Reader) {
+	if !r.enterNested() {
+		return
+	}
+	d.decode(ptr, r)
+	r.leaveNested()
+}
+
+func (d *mapDecoder) decode(ptr unsafe.Pointer, r *Reader) {
 	if d.mapType.UnsafeIsNil(ptr) {
 		d.mapType.UnsafeSet(ptr, d.mapType.UnsafeMakeMap(0))
 	}
@@ -66,23 +77,23 @@ func (d *mapDecoder) Decode(ptr unsafe.Pointer, r *Reader) {
 		if l == 0 {
 			break
 		}
+		if !r.allocate(l, int64(d.mapType.Key().Type1().Size()+d.elemType.Type1().Size())) {
+			return
+		}
 
 		for range l {
-			keyPtr := reflect2.PtrOf(r.ReadString())
+			key := r.ReadString()
 			elemPtr := d.elemType.UnsafeNew()
+			start := r.inputOffset()
 			d.decoder.Decode(elemPtr, r)
 			if r.Error != nil {
-				r.Error = fmt.Errorf("reading map[string]%s: %w", d.elemType.String(), r.Error)
+				r.wrapDecodeError("["+strconv.Quote(key)+"]", d.values, start)
 				return
 			}
 
-			d.mapType.UnsafeSetIndex(ptr, keyPtr, elemPtr)
+			d.mapType.UnsafeSetIndex(ptr, reflect2.PtrOf(key), elemPtr)
 		}
 	}
-
-	if r.Error != nil && !errors.Is(r.Error, io.EOF) {
-		r.Error = fmt.Errorf("%v: %w", d.mapType, r.Error)
-	}
 }
 
 func decoderOfMapUnmarshaler(d *decoderContext, m *MapSchema, typ reflect2.Type) ValDecoder {
@@ -93,6 +104,7 @@ func decoderOfMapUnmarshaler(d *decoderContext, m *MapSchema, typ reflect2.Type)
 		mapType:  mapType,
 		keyType:  mapType.Key(),
 		elemType: mapType.Elem(),
+		values:   m.Values(),
 		decoder:  decoder,
 	}
 }
@@ -101,10 +113,19 @@ type mapDecoderUnmarshaler struct {
 	mapType  *reflect2.UnsafeMapType
 	keyType  reflect2.Type
 	elemType reflect2.Type
+	values   Schema
 	decoder  ValDecoder
 }
 
 func (d *mapDecoderUnmarshaler) Decode(ptr unsafe.Pointer, r *Reader) {
+	if !r.enterNested() {
+		return
+	}
+	d.decode(ptr, r)
+	r.leaveNested()
+}
+
+func (d *mapDecoderUnmarshaler) decode(ptr unsafe.Pointer, r *Reader) {
 	if d.mapType.UnsafeIsNil(ptr) {
 		d.mapType.UnsafeSet(ptr, d.mapType.UnsafeMakeMap(0))
 	}
@@ -114,6 +135,9 @@ func (d *mapDecoderUnmarshaler) Decode(ptr unsafe.Pointer, r *Reader) {
 		if l == 0 {
 			break
 		}
+		if !r.allocate(l, int64(d.mapType.Key().Type1().Size()+d.elemType.Type1().Size())) {
+			return
+		}
 
 		for range l {
 			keyPtr := d.keyType.UnsafeNew()
@@ -125,22 +149,24 @@ func (d *mapDecoderUnmarshaler) Decode(ptr unsafe.Pointer, r *Reader) {
 				keyObj = d.keyType.UnsafeIndirect(keyPtr)
 			}
 			unmarshaler := keyObj.(encoding.TextUnmarshaler)
-			err := unmarshaler.UnmarshalText([]byte(r.ReadString()))
+			key := r.ReadString()
+			err := unmarshaler.UnmarshalText([]byte(key))
 			if err != nil {
 				r.ReportError("mapDecoderUnmarshaler", err.Error())
 				return
 			}
 
 			elemPtr := d.elemType.UnsafeNew()
+			start := r.inputOffset()
 			d.decoder.Decode(elemPtr, r)
+			if r.Error != nil && !errors.Is(r.Error, io.EOF) {
+				r.wrapDecodeError("["+strconv.Quote(key)+"]", d.values, start)
+				return
+			}
 
 			d.mapType.UnsafeSetIndex(ptr, keyPtr, elemPtr)
 		}
 	}
-
-	if r.Error != nil && !errors.Is(r.Error, io.EOF) {
-		r.Error = fmt.Errorf("%v: %w", d.mapType, r.Error)
-	}
 }
 
 func encoderOfMap(e *encoderContext, m *MapSchema, typ reflect2.Type) ValEncoder {
@@ -63,6 +63,8 @@ func decoderOfStruct(d *decoderContext, schema Schema, typ reflect2.Type) ValDec
 	for _, field := range rec.Fields() {
 		if field.action == FieldIgnore {
 			fields = append(fields, &structFieldDecoder{
+				name:    field.Name(),
+				schema:  field.Type(),
 				decoder: createSkipDecoder(field.Type()),
 			})
 			continue
@@ -89,6 +91,8 @@ func decoderOfStruct(d *decoderContext, schema Schema, typ reflect2.Type) ValDec
 			}
 
 			fields = append(fields, &structFieldDecoder{
+				name:    field.Name(),
+				schema:  field.Type(),
 				decoder: createSkipDecoder(field.Type()),
 			})
 			continue
@@ -97,6 +101,8 @@ func decoderOfStruct(d *decoderContext, schema Schema, typ reflect2.Type) ValDec
 		if field.action == FieldSetDefault {
 			if field.hasDef {
 				fields = append(fields, &structFieldDecoder{
+					name:    field.Name(),
+					schema:  field.Type(),
 					field:   sf.Field,
 					decoder: createDefaultDecoder(d, field, sf.Field[len(sf.Field)-1].Type()),
 				})
@@ -107,6 +113,8 @@ func decoderOfStruct(d *decoderContext, schema Schema, typ reflect2.Type) ValDec
 
 		dec := decoderOfType(d, field.Type(), sf.Field[len(sf.Field)-1].Type())
 		fields = append(fields, &structFieldDecoder{
+			name:    field.Name(),
+			schema:  field.Type(),
 			field:   sf.Field,
 			decoder: dec,
 		})
@@ -116,6 +124,8 @@ func decoderOfStruct(d *decoderContext, schema Schema, typ reflect2.Type) ValDec
 }
 
 type structFieldDecoder struct {
+	name    string
+	schema  Schema
 	field   []*reflect2.UnsafeStructField
 	decoder ValDecoder
 }
@@ -126,10 +136,24 @@ type structDecoder struct {
 }
 
 func (d *structDecoder) Decode(ptr unsafe.Pointer, r *Reader) {
+	if !r.enterNested() {
+		return
+	}
+	d.decode(ptr, r)
+	r.leaveNested()
+}
+
+func (d *structDecoder) decode(ptr unsafe.Pointer, r *Reader) {
 	for _, field := range d.fields {
 		// Skip case
+		start := r.inputOffset()
+
 		if field.field == nil {
 			field.decoder.Decode(nil, r)
+			if r.Error != nil && !errors.Is(r.Error, io.EOF) {
+				r.wrapDecodeError(field.name, field.schema, start)
+				return
+			}
 			continue
 		}
 
@@ -153,10 +177,8 @@ func (d *structDecoder) Decode(ptr unsafe.Pointer, r *Reader) {
 		field.decoder.Decode(fieldPtr, r)
 
 		if r.Error != nil && !errors.Is(r.Error, io.EOF) {
-			for _, f := range field.field {
-				r.Error = fmt.Errorf("%s: %w", f.Name(), r.Error)
-				return
-			}
+			r.wrapDecodeError(field.name, field.schema, start)
+			return
 		}
 	}
 }
@@ -268,6 +290,7 @@ func decoderOfRecord(d *decoderContext, schema Schema, typ reflect2.Type) ValDec
 		case FieldIgnore:
 			fields[i] = recordMapDecoderField{
 				name:    field.Name(),
+				schema:  field.Type(),
 				decoder: createSkipDecoder(field.Type()),
 				skip:    true,
 			}
@@ -276,6 +299,7 @@ func decoderOfRecord(d *decoderContext, schema Schema, typ reflect2.Type) ValDec
 			if field.hasDef {
 				fields[i] = recordMapDecoderField{
 					name:    field.Name(),
+					schema:  field.Type(),
 					decoder: createDefaultDecoder(d, field, mapType.Elem()),
 				}
 				continue
@@ -284,6 +308,7 @@ func decoderOfRecord(d *decoderContext, schema Schema, typ reflect2.Type) ValDec
 
 		fields[i] = recordMapDecoderField{
 			name:    field.Name(),
+			schema:  field.Type(),
 			decoder: decoderOfType(d, field.Type(), mapType.Elem()),
 		}
 	}
@@ -297,6 +322,7 @@ func decoderOfRecord(d *decoderContext, schema Schema, typ reflect2.Type) ValDec
 
 type recordMapDecoderField struct {
 	name    string
+	schema  Schema
 	decoder ValDecoder
 	skip    bool
 }
@@ -308,23 +334,32 @@ type recordMapDecoder struct {
 }
 
 func (d *recordMapDecoder) Decode(ptr unsafe.Pointer, r *Reader) {
+	if !r.enterNested() {
+		return
+	}
+	d.decode(ptr, r)
+	r.leaveNested()
+}
+
+func (d *recordMapDecoder) decode(ptr unsafe.Pointer, r *Reader) {
 	if d.mapType.UnsafeIsNil(ptr) {
 		d.mapType.UnsafeSet(ptr, d.mapType.UnsafeMakeMap(len(d.fields)))
 	}
 
 	for _, field := range d.fields {
 		elemPtr := d.elemType.UnsafeNew()
+		start := r.inputOffset()
 		field.decoder.Decode(elemPtr, r)
+		if r.Error != nil && !errors.Is(r.Error, io.EOF) {
+			r.wrapDecodeError(field.name, field.schema, start)
+			return
+		}
 		if field.skip {
 			continue
 		}
 
 		d.mapType.UnsafeSetIndex(ptr, reflect2.PtrOf(field), elemPtr)
 	}
-
-	if r.Error != nil && !errors.Is(r.Error, io.EOF) {
-		r.Error = fmt.Errorf("%v: %w", d.mapType, r.Error)
-	}
 }
 
 func encoderOfRecord(e *encoderContext, rec *RecordSchema, typ reflect2.Type) ValEncoder {
@@ -114,9 +114,13 @@ type recordSkipDecoder struct {
 }
 
 func (d *recordSkipDecoder) Decode(_ unsafe.Pointer, r *Reader) {
+	if !r.enterNested() {
+		return
+	}
 	for _, decoder := range d.decoders {
 		decoder.Decode(nil, r)
 	}
+	r.leaveNested()
 }
 
 type enumSkipDecoder struct {
@@ -141,6 +145,14 @@ type sliceSkipDecoder struct {
 }
 
 func (d *sliceSkipDecoder) Decode(_ unsafe.Pointer, r *Reader) {
+	if !r.enterNested() {
+		return
+	}
+	d.skip(r)
+	r.leaveNested()
+}
+
+func (d *sliceSkipDecoder) skip(r *Reader) {
 	for {
 		l, size := r.ReadBlockHeader()
 		if l == 0 {
@@ -154,6 +166,9 @@ func (d *sliceSkipDecoder) Decode(_ unsafe.Pointer, r *Reader) {
 
 		for range l {
 			d.decoder.Decode(nil, r)
+			if r.Error != nil {
+				return
+			}
 		}
 	}
 }
@@ -172,6 +187,14 @@ type mapSkipDecoder struct {
 }
 
 func (d *mapSkipDecoder) Decode(_ unsafe.Pointer, r *Reader) {
+	if !r.enterNested() {
+		return
+	}
+	d.skip(r)
+	r.leaveNested()
+}
+
+func (d *mapSkipDecoder) skip(r *Reader) {
 	for {
 		l, size := r.ReadBlockHeader()
 		if l == 0 {
@@ -186,6 +209,9 @@ func (d *mapSkipDecoder) Decode(_ unsafe.Pointer, r *Reader) {
 		for range l {
 			r.SkipString()
 			d.decoder.Decode(nil, r)
+			if r.Error != nil {
+				return
+			}
 		}
 	}
 }
@@ -3,7 +3,9 @@ package avro
 import (
 	"errors"
 	"io"
+	"reflect"
 	"sync"
+	"time"
 
 	"github.com/modern-go/reflect2"
 )
@@ -48,6 +50,15 @@ type Config struct {
 	// call to Marshal() and Unmarshal()
 	DisableCaching bool
 
+	// MaxCacheEntries is the maximum number of encoders, and of decoders, that are cached.
+	// When exceeded, the least recently used encoder or decoder is evicted.
+	// This defaults to no maximum.
+	MaxCacheEntries int
+
+	// CacheTTL is the duration after which a cached encoder or decoder that has not been
+	// used is evicted. This defaults to no expiry.
+	CacheTTL time.Duration
+
 	// MaxByteSliceSize is the maximum size of `bytes` or `string` types the Reader will create, defaulting to 1MiB.
 	// If this size is exceeded, the Reader returns an error. This can be disabled by setting a negative number.
 	MaxByteSliceSize int
@@ -56,14 +67,34 @@ type Config struct {
 	// allocation size by default.
 	// If this size is exceeded, the decoder returns an error.
 	MaxSliceAllocSize int
+
+	// MaxDepth is the maximum nesting depth of records, arrays and maps the decoder will decode,
+	// including values that are skipped. If this depth is exceeded, the decoder returns a *LimitError.
+	// This defaults to no maximum.
+	MaxDepth int
+
+	// MaxTotalAllocation is the maximum total size, in bytes, of the `bytes`, `string`, array and map
+	// values the decoder will allocate for a single decoded value. If this size is exceeded, the decoder
+	// returns a *LimitError. This defaults to no maximum.
+	MaxTotalAllocation int
+
+	// ZeroCopy makes Unmarshal, and a Reader reading from a byte slice, decode `bytes` and `string`
+	// values that alias the input, rather than copies of it.
+	// The input must not be modified while the decoded values are in use, and decoded `bytes` values
+	// must not be modified, as they share memory with the input.
+	// Decoders reading from an io.Reader always copy, as their buffer is reused.
+	ZeroCopy bool
 }
 
 // Freeze makes the configuration immutable.
 func (c Config) Freeze() API {
 	api := &frozenConfig{
 		config:         c,
+		decoderCache:   newCodecCache(c.MaxCacheEntries, c.CacheTTL),
+		encoderCache:   newCodecCache(c.MaxCacheEntries, c.CacheTTL),
 		resolver:       NewTypeResolver(),
 		typeConverters: NewTypeConverters(),
+		limits:         c.MaxDepth > 0 || c.MaxTotalAllocation > 0,
 	}
 
 	api.readerPool = &sync.Pool{
@@ -96,10 +127,22 @@ type API interface {
 	// Marshal returns the Avro encoding of v.
 	Marshal(schema Schema, v any) ([]byte, error)
 
+	// AppendMarshal appends the Avro encoding of v to dst and returns the extended buffer.
+	// If an error occurs, dst is returned unchanged.
+	AppendMarshal(schema Schema, dst []byte, v any) ([]byte, error)
+
 	// Unmarshal parses the Avro encoded data and stores the result in the value pointed to by v.
 	// If v is nil or not a pointer, Unmarshal returns an error.
 	Unmarshal(schema Schema, data []byte, v any) error
 
+	// Validate checks that v conforms to the schema, without encoding it.
+	// If it does not, a *ValidationError with all violations is returned.
+	Validate(schema Schema, v any) error
+
+	// CheckType returns every field of typ that cannot encode or decode the schema,
+	// including missing required fields and union branches without a Go type.
+	CheckType(schema Schema, typ reflect.Type) []TypeMismatch
+
 	// NewEncoder returns a new encoder that writes to w using schema.
 	NewEncoder(schema Schema, w io.Writer) *Encoder
 
@@ -123,13 +166,16 @@ type API interface {
 
 	// NamesOf returns the names associated with a given type.
 	NamesOf(typ reflect2.Type) ([]string, error)
+
+	// CacheStats returns the statistics of the encoder and decoder caches.
+	CacheStats() CacheStats
 }
 
 type frozenConfig struct {
 	config Config
 
-	decoderCache sync.Map // map[cacheKey]ValDecoder
-	encoderCache sync.Map // map[cacheKey]ValEncoder
+	decoderCache *codecCache // ValDecoder
+	encoderCache *codecCache // ValEncoder
 
 	readerPool *sync.Pool
 	writerPool *sync.Pool
@@ -137,6 +183,10 @@ type frozenConfig struct {
 	resolver *TypeResolver
 
 	typeConverters *TypeConverters
+
+	// limits is true if MaxDepth or MaxTotalAllocation is set, so that the
+	// decoders only track the depth and allocation of values when needed.
+	limits bool
 }
 
 func (c *frozenConfig) Marshal(schema Schema, v any) ([]byte, error) {
@@ -155,6 +205,15 @@ func (c *frozenConfig) Marshal(schema Schema, v any) ([]byte, error) {
 	return copied, nil
 }
 
+func (c *frozenConfig) AppendMarshal(schema Schema, dst []byte, v any) ([]byte, error) {
+	writer := c.borrowWriter()
+	defer c.returnWriter(writer)
+
+	return writer.appendTo(dst, func() {
+		writer.WriteVal(schema, v)
+	})
+}
+
 func (c *frozenConfig) borrowWriter() *Writer {
 	writer := c.writerPool.Get().(*Writer)
 	writer.Reset(nil)
@@ -173,6 +232,7 @@ func (c *frozenConfig) Unmarshal(schema Schema, data []byte, v any) error {
 	defer c.returnReader(reader)
 
 	reader.ReadVal(schema, v)
+	reader.finishDecodeError(schema, 0)
 	err := reader.Error
 
 	if errors.Is(err, io.EOF) {
@@ -297,6 +357,18 @@ func (c *frozenConfig) getMaxByteSliceSize() int {
 	return size
 }
 
+func (c *frozenConfig) getZeroCopy() bool {
+	return c.config.ZeroCopy
+}
+
+func (c *frozenConfig) getMaxDepth() int {
+	return c.config.MaxDepth
+}
+
+func (c *frozenConfig) getMaxTotalAllocation() int64 {
+	return int64(c.config.MaxTotalAllocation)
+}
+
 func (c *frozenConfig) getMaxSliceAllocSize() int {
 	size := c.config.MaxSliceAllocSize
 	if size > maxAllocSize || size <= 0 {
@@ -2,6 +2,7 @@ package avro
 
 import (
 	"testing"
+	"time"
 
 	"github.com/modern-go/reflect2"
 	"github.com/stretchr/testify/assert"
@@ -182,3 +183,88 @@ func TestConfig_DisableCache_DoesNotReuseEncoders(t *testing.T) {
 
 	assert.NotSame(t, enc1, enc2)
 }
+
+func TestConfig_MaxCacheEntries_EvictsLeastRecentlyUsed(t *testing.T) {
+	type testObj struct {
+		A int64 `avro:"a"`
+	}
+
+	api := Config{MaxCacheEntries: 2}.Freeze()
+	cfg := api.(*frozenConfig)
+	cfg.decoderCache.now = func() time.Time {
+		t.Fatal("time should not be read without a TTL")
+		return time.Time{}
+	}
+
+	schema1 := MustParse(`{"type": "record", "name": "test1", "fields": [{"name": "a", "type": "long"}]}`)
+	schema2 := MustParse(`{"type": "record", "name": "test2", "fields": [{"name": "a", "type": "long"}]}`)
+	schema3 := MustParse(`{"type": "record", "name": "test3", "fields": [{"name": "a", "type": "long"}]}`)
+	typ := reflect2.TypeOfPtr(&testObj{})
+
+	dec1 := cfg.DecoderOf(schema1, typ)
+	dec2 := cfg.DecoderOf(schema2, typ)
+	assert.Same(t, dec1, cfg.DecoderOf(schema1, typ))
+	_ = cfg.DecoderOf(schema3, typ)
+
+	assert.Same(t, dec1, cfg.DecoderOf(schema1, typ))
+	assert.NotSame(t, dec2, cfg.DecoderOf(schema2, typ))
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 4, Evictions: 2, Entries: 2}, cfg.CacheStats())
+}
+
+func TestConfig_CacheTTL_EvictsUnusedEntries(t *testing.T) {
+	type testObj struct {
+		A int64 `avro:"a"`
+	}
+
+	api := Config{CacheTTL: time.Minute}.Freeze()
+	cfg := api.(*frozenConfig)
+	now := time.Now()
+	cfg.encoderCache.now = func() time.Time { return now }
+
+	schema := MustParse(`{"type": "record", "name": "test", "fields": [{"name": "a", "type": "long"}]}`)
+	typ := reflect2.TypeOfPtr(testObj{})
+
+	enc1 := cfg.EncoderOf(schema, typ)
+	now = now.Add(30 * time.Second)
+	assert.Same(t, enc1, cfg.EncoderOf(schema, typ))
+	now = now.Add(30 * time.Second)
+	assert.Same(t, enc1, cfg.EncoderOf(schema, typ))
+	now = now.Add(2 * time.Minute)
+	assert.NotSame(t, enc1, cfg.EncoderOf(schema, typ))
+
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 2, Evictions: 1, Entries: 1}, cfg.CacheStats())
+}
+
+func TestConfig_CacheStats(t *testing.T) {
+	type testObj struct {
+		A int64 `avro:"a"`
+	}
+
+	api := Config{MaxCacheEntries: 10}.Freeze()
+	schema := MustParse(`{"type": "record", "name": "test", "fields": [{"name": "a", "type": "long"}]}`)
+
+	b, err := api.Marshal(schema, testObj{A: 1})
+	assert.NoError(t, err)
+	var got testObj
+	assert.NoError(t, api.Unmarshal(schema, b, &got))
+	assert.NoError(t, api.Unmarshal(schema, b, &got))
+
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 2, Entries: 2}, api.CacheStats())
+}
+
+func TestConfig_CacheStatsUnbounded(t *testing.T) {
+	type testObj struct {
+		A int64 `avro:"a"`
+	}
+
+	api := Config{}.Freeze()
+	schema := MustParse(`{"type": "record", "name": "test", "fields": [{"name": "a", "type": "long"}]}`)
+
+	b, err := api.Marshal(schema, testObj{A: 1})
+	assert.NoError(t, err)
+	var got testObj
+	assert.NoError(t, api.Unmarshal(schema, b, &got))
+	assert.NoError(t, api.Unmarshal(schema, b, &got))
+
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 2, Entries: 2}, api.CacheStats())
+}
@@ -1,9 +1,59 @@
 package avro
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"iter"
+	"strings"
 )
 
+// DecodeError is an error that occurred while decoding a value, with the
+// location of the error in the value and the input.
+type DecodeError struct {
+	// Path is the path of the value being decoded from the decoded value,
+	// e.g. order.items[3].price. It is empty for the decoded value.
+	Path string
+
+	// Offset is the offset of the input, in bytes, at which the value being
+	// decoded starts.
+	Offset int64
+
+	// Schema is the schema of the value being decoded.
+	Schema Schema
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the location and the underlying error.
+func (e *DecodeError) Error() string {
+	msg := strings.TrimPrefix(e.Err.Error(), "avro: ")
+	if e.Path == "" {
+		return fmt.Sprintf("avro: decoding at offset %d: %s", e.Offset, msg)
+	}
+	return fmt.Sprintf("avro: decoding %s at offset %d: %s", e.Path, e.Offset, msg)
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// LimitError is an error returned when decoding a value exceeds a limit of the Config.
+type LimitError struct {
+	// Limit is the name of the Config field of the limit, i.e. MaxDepth or MaxTotalAllocation.
+	Limit string
+
+	// Max is the value of the limit.
+	Max int64
+}
+
+// Error returns the exceeded limit.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("avro: exceeded `Config.%s` of %d", e.Limit, e.Max)
+}
+
 // Decoder reads and decodes Avro values from an input stream.
 type Decoder struct {
 	s Schema
@@ -33,7 +83,10 @@ func (d *Decoder) Decode(v any) error {
 		}
 	}
 
+	d.r.resetLimits()
+	start := d.r.inputOffset()
 	d.r.ReadVal(d.s, v)
+	d.r.finishDecodeError(d.s, start)
 
 	//nolint:errorlint // Only direct EOF errors should be discarded.
 	if d.r.Error == io.EOF {
@@ -42,6 +95,64 @@ func (d *Decoder) Decode(v any) error {
 	return d.r.Error
 }
 
+// DecodeGeneric reads the next Avro encoded value from its input as a generic value.
+// See Reader.ReadNext for the types of the returned values.
+func (d *Decoder) DecodeGeneric() (any, error) {
+	if d.r.head == d.r.tail && d.r.reader != nil {
+		if !d.r.loadMore() {
+			return nil, io.EOF
+		}
+	}
+
+	d.r.resetLimits()
+	start := d.r.inputOffset()
+	v := d.r.ReadNext(d.s)
+	d.r.finishDecodeError(d.s, start)
+
+	//nolint:errorlint // Only direct EOF errors should be discarded.
+	if d.r.Error == io.EOF {
+		return v, nil
+	}
+	return v, d.r.Error
+}
+
+// All returns an iterator over the values decoded from dec as type T.
+// Iteration stops at the end of the input, or after the first error is yielded.
+//
+// Breaking out of the loop leaves dec positioned after the last yielded value.
+func All[T any](dec *Decoder) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			var v T
+			err := dec.Decode(&v)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// AllGeneric returns an iterator over the values decoded from dec as generic values.
+// Iteration stops at the end of the input, or after the first error is yielded.
+//
+// Breaking out of the loop leaves dec positioned after the last yielded value.
+func AllGeneric(dec *Decoder) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		for {
+			v, err := dec.DecodeGeneric()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
 // Unmarshal parses the Avro encoded data and stores the result in the value pointed to by v.
 // If v is nil or not a pointer, Unmarshal returns an error.
 func Unmarshal(schema Schema, data []byte, v any) error {
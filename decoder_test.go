@@ -2,10 +2,15 @@ package avro_test
 
 import (
 	"bytes"
+	"errors"
+	"io"
+	"strconv"
 	"testing"
+	"unsafe"
 
 	"github.com/hamba/avro/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewDecoder_SchemaError(t *testing.T) {
@@ -99,3 +104,432 @@ func TestUnmarshal_NilPtr(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestDecoder_DecodeGeneric(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x36, 0x06, 0x66, 0x6f, 0x6f}
+	schema := avro.MustParse(`{
+	"type": "record",
+	"name": "test",
+	"fields" : [
+		{"name": "a", "type": "long"},
+		{"name": "b", "type": "string"}
+	]
+}`)
+	dec := avro.NewDecoderForSchema(schema, bytes.NewReader(data))
+
+	got, err := dec.DecodeGeneric()
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": int64(27), "b": "foo"}, got)
+}
+
+func TestAll(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x36, 0x06, 0x66, 0x6f, 0x6f, 0x02, 0x06, 0x62, 0x61, 0x72}
+	schema := avro.MustParse(`{
+	"type": "record",
+	"name": "test",
+	"fields" : [
+		{"name": "a", "type": "long"},
+		{"name": "b", "type": "string"}
+	]
+}`)
+	dec := avro.NewDecoderForSchema(schema, bytes.NewReader(data))
+
+	var got []TestRecord
+	for rec, err := range avro.All[TestRecord](dec) {
+		require.NoError(t, err)
+		got = append(got, rec)
+	}
+
+	assert.Equal(t, []TestRecord{{A: 27, B: "foo"}, {A: 1, B: "bar"}}, got)
+}
+
+func TestAll_Break(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x36, 0x06, 0x66, 0x6f, 0x6f, 0x02, 0x06, 0x62, 0x61, 0x72}
+	schema := avro.MustParse(`{
+	"type": "record",
+	"name": "test",
+	"fields" : [
+		{"name": "a", "type": "long"},
+		{"name": "b", "type": "string"}
+	]
+}`)
+	dec := avro.NewDecoderForSchema(schema, bytes.NewReader(data))
+
+	for rec, err := range avro.All[TestRecord](dec) {
+		require.NoError(t, err)
+		assert.Equal(t, TestRecord{A: 27, B: "foo"}, rec)
+		break
+	}
+
+	// The decoder continues after the last yielded value.
+	var rec TestRecord
+	err := dec.Decode(&rec)
+
+	require.NoError(t, err)
+	assert.Equal(t, TestRecord{A: 1, B: "bar"}, rec)
+}
+
+func TestAll_Error(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x36, 0x06, 0x66}
+	schema := avro.MustParse(`{
+	"type": "record",
+	"name": "test",
+	"fields" : [
+		{"name": "a", "type": "long"},
+		{"name": "b", "type": "string"}
+	]
+}`)
+	dec := avro.NewDecoderForSchema(schema, bytes.NewReader(data))
+
+	var errs []error
+	for _, err := range avro.All[TestRecord](dec) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	assert.Error(t, errs[0])
+}
+
+func TestAllGeneric(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x36, 0x02}
+	dec := avro.NewDecoderForSchema(avro.MustParse("long"), bytes.NewReader(data))
+
+	var got []any
+	for v, err := range avro.AllGeneric(dec) {
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+
+	assert.Equal(t, []any{int64(27), int64(1)}, got)
+}
+
+func TestDecoder_DecodeErrorPath(t *testing.T) {
+	defer ConfigTeardown()
+
+	type item struct {
+		Name string `avro:"name"`
+	}
+	type order struct {
+		ID    int64           `avro:"id"`
+		Items []item          `avro:"items"`
+		Tags  map[string]item `avro:"tags"`
+	}
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "order",
+		"fields": [
+			{"name": "id", "type": "long"},
+			{"name": "items", "type": {"type": "array", "items": {
+				"type": "record",
+				"name": "item",
+				"fields": [{"name": "name", "type": "string"}]
+			}}},
+			{"name": "tags", "type": {"type": "map", "values": "item"}}
+		]
+	}`)
+
+	tests := []struct {
+		name       string
+		data       []byte
+		wantPath   string
+		wantOffset int64
+		wantSchema avro.Schema
+		wantErr    string
+	}{
+		{
+			name:       "array item",
+			data:       []byte{0x02, 0x04, 0x02, 'a', 0x01},
+			wantPath:   "items[1].name",
+			wantOffset: 4,
+			wantSchema: avro.NewPrimitiveSchema(avro.String, nil),
+			wantErr:    "avro: decoding items[1].name at offset 4: ReadSTRING: invalid string length",
+		},
+		{
+			name:       "map value",
+			data:       []byte{0x02, 0x00, 0x02, 0x02, 'k', 0x01},
+			wantPath:   `tags["k"].name`,
+			wantOffset: 5,
+			wantSchema: avro.NewPrimitiveSchema(avro.String, nil),
+			wantErr:    `avro: decoding tags["k"].name at offset 5: ReadSTRING: invalid string length`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for name, decode := range map[string]func() error{
+				"typed": func() error {
+					var got order
+					return avro.Unmarshal(schema, test.data, &got)
+				},
+				"generic": func() error {
+					var got any
+					return avro.Unmarshal(schema, test.data, &got)
+				},
+				"read next": func() error {
+					dec := avro.NewDecoderForSchema(schema, bytes.NewReader(test.data))
+					_, err := dec.DecodeGeneric()
+					return err
+				},
+			} {
+				t.Run(name, func(t *testing.T) {
+					err := decode()
+
+					var derr *avro.DecodeError
+					require.ErrorAs(t, err, &derr)
+					assert.Equal(t, test.wantPath, derr.Path)
+					assert.Equal(t, test.wantOffset, derr.Offset)
+					assert.Equal(t, test.wantSchema.Fingerprint(), derr.Schema.Fingerprint())
+					assert.EqualError(t, err, test.wantErr)
+				})
+			}
+		})
+	}
+}
+
+func TestDecoder_DecodeErrorTopLevel(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("long")
+	data := []byte{0x02, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	dec := avro.NewDecoderForSchema(schema, bytes.NewReader(data))
+
+	var got int64
+	require.NoError(t, dec.Decode(&got))
+	err := dec.Decode(&got)
+
+	var derr *avro.DecodeError
+	require.ErrorAs(t, err, &derr)
+	assert.Equal(t, "", derr.Path)
+	assert.Equal(t, int64(1), derr.Offset)
+	assert.Equal(t, schema, derr.Schema)
+}
+
+func TestUnmarshal_DecodeErrorAfterTypeError(t *testing.T) {
+	defer ConfigTeardown()
+
+	type testObj struct {
+		A string `avro:"a"`
+	}
+
+	schema := avro.MustParse(`{"type": "record", "name": "test", "fields": [{"name": "a", "type": "string"}]}`)
+
+	var bad int
+	err := avro.Unmarshal(schema, []byte{0x02, 'a'}, &bad)
+	require.EqualError(t, err, "avro: int is unsupported for avro record")
+	assert.False(t, errors.As(err, new(*avro.DecodeError)))
+
+	var got testObj
+	err = avro.Unmarshal(schema, []byte{0x01}, &got)
+
+	var derr *avro.DecodeError
+	require.ErrorAs(t, err, &derr)
+	assert.Equal(t, "a", derr.Path)
+	assert.Equal(t, int64(0), derr.Offset)
+}
+
+func TestDecoder_MaxDepth(t *testing.T) {
+	defer ConfigTeardown()
+
+	type node struct {
+		Next *node `avro:"next"`
+	}
+	type wrapper struct {
+		Node node  `avro:"node"`
+		ID   int32 `avro:"id"`
+	}
+	type skipWrapper struct {
+		ID int32 `avro:"id"`
+	}
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "wrapper",
+		"fields": [
+			{"name": "node", "type": {
+				"type": "record",
+				"name": "node",
+				"fields": [{"name": "next", "type": ["null", "node"]}]
+			}},
+			{"name": "id", "type": "int"}
+		]
+	}`)
+	api := avro.Config{MaxDepth: 4}.Freeze()
+
+	for name, decode := range map[string]func([]byte) error{
+		"typed": func(data []byte) error {
+			var got wrapper
+			return api.Unmarshal(schema, data, &got)
+		},
+		"generic": func(data []byte) error {
+			var got any
+			return api.Unmarshal(schema, data, &got)
+		},
+		"read next": func(data []byte) error {
+			dec := api.NewDecoder(schema, bytes.NewReader(data))
+			_, err := dec.DecodeGeneric()
+			return err
+		},
+		"skip": func(data []byte) error {
+			var got skipWrapper
+			return api.Unmarshal(schema, data, &got)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := decode([]byte{0x02, 0x02, 0x00, 0x02})
+			require.NoError(t, err)
+
+			err = decode([]byte{0x02, 0x02, 0x02, 0x02, 0x00, 0x02})
+
+			var lerr *avro.LimitError
+			require.ErrorAs(t, err, &lerr)
+			assert.Equal(t, "MaxDepth", lerr.Limit)
+			assert.Equal(t, int64(4), lerr.Max)
+		})
+	}
+}
+
+func TestDecoder_MaxTotalAllocation(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{"type": "array", "items": "string"}`)
+	api := avro.Config{MaxTotalAllocation: 64}.Freeze()
+	data := []byte{0x04, 0x06, 'a', 'b', 'c', 0x06, 'd', 'e', 'f', 0x00}
+
+	for name, decode := range map[string]func(avro.API) error{
+		"typed": func(api avro.API) error {
+			var got []string
+			return api.Unmarshal(schema, data, &got)
+		},
+		"generic": func(api avro.API) error {
+			var got any
+			return api.Unmarshal(schema, data, &got)
+		},
+		"read next": func(api avro.API) error {
+			dec := api.NewDecoder(schema, bytes.NewReader(data))
+			_, err := dec.DecodeGeneric()
+			return err
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, decode(api))
+
+			err := decode(avro.Config{MaxTotalAllocation: 32}.Freeze())
+
+			var lerr *avro.LimitError
+			require.ErrorAs(t, err, &lerr)
+			assert.Equal(t, "MaxTotalAllocation", lerr.Limit)
+			assert.Equal(t, int64(32), lerr.Max)
+		})
+	}
+}
+
+func TestDecoder_MaxTotalAllocationPerValue(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("string")
+	api := avro.Config{MaxTotalAllocation: 4}.Freeze()
+	data := []byte{0x06, 'a', 'b', 'c', 0x06, 'd', 'e', 'f'}
+	dec := api.NewDecoder(schema, bytes.NewReader(data))
+
+	var got string
+	require.NoError(t, dec.Decode(&got))
+	require.NoError(t, dec.Decode(&got))
+	assert.Equal(t, "def", got)
+}
+
+func TestUnmarshal_ZeroCopy(t *testing.T) {
+	defer ConfigTeardown()
+
+	type testObj struct {
+		S string `avro:"s"`
+		B []byte `avro:"b"`
+	}
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "test",
+		"fields": [
+			{"name": "s", "type": "string"},
+			{"name": "b", "type": "bytes"}
+		]
+	}`)
+	api := avro.Config{ZeroCopy: true}.Freeze()
+	data := []byte{0x06, 'f', 'o', 'o', 0x04, 0xec, 0xab}
+
+	var got testObj
+	err := api.Unmarshal(schema, data, &got)
+
+	require.NoError(t, err)
+	assert.Equal(t, "foo", got.S)
+	assert.Equal(t, []byte{0xec, 0xab}, got.B)
+	assert.Same(t, &data[1], unsafe.StringData(got.S))
+	assert.Same(t, &data[5], &got.B[0])
+	assert.Equal(t, 2, cap(got.B))
+}
+
+func TestUnmarshal_ZeroCopyGeneric(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{"type": "map", "values": "bytes"}`)
+	api := avro.Config{ZeroCopy: true}.Freeze()
+	data := []byte{0x02, 0x02, 'k', 0x04, 0xec, 0xab, 0x00}
+
+	var got any
+	err := api.Unmarshal(schema, data, &got)
+
+	require.NoError(t, err)
+	b := got.(map[string]any)["k"].([]byte)
+	assert.Same(t, &data[4], &b[0])
+}
+
+func TestUnmarshal_ShortBuffer(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{"type": "record", "name": "test", "fields": [{"name": "a", "type": "string"}]}`)
+	data := []byte{0x06, 'f', 'o'}
+
+	for _, zeroCopy := range []bool{false, true} {
+		t.Run(strconv.FormatBool(zeroCopy), func(t *testing.T) {
+			api := avro.Config{ZeroCopy: zeroCopy}.Freeze()
+
+			var got struct {
+				A string `avro:"a"`
+			}
+			err := api.Unmarshal(schema, data, &got)
+
+			require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+			var derr *avro.DecodeError
+			require.ErrorAs(t, err, &derr)
+			assert.Equal(t, "a", derr.Path)
+		})
+	}
+}
+
+func TestDecoder_ZeroCopyCopiesFromReader(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("bytes")
+	api := avro.Config{ZeroCopy: true}.Freeze()
+	data := []byte{0x04, 0xec, 0xab}
+	dec := api.NewDecoder(schema, bytes.NewReader(data))
+
+	var got []byte
+	err := dec.Decode(&got)
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xec, 0xab}, got)
+	data[1] = 0
+	assert.Equal(t, []byte{0xec, 0xab}, got)
+}
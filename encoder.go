@@ -31,6 +31,14 @@ func (e *Encoder) Encode(v any) error {
 	return e.w.Error
 }
 
+// AppendEncode appends the Avro encoding of v to dst and returns the extended buffer,
+// rather than writing it to the stream. If an error occurs, dst is returned unchanged.
+func (e *Encoder) AppendEncode(dst []byte, v any) ([]byte, error) {
+	return e.w.appendTo(dst, func() {
+		e.w.WriteVal(e.s, v)
+	})
+}
+
 // Reset resets the encoder to write to a new io.Writer.
 func (e *Encoder) Reset(w io.Writer) {
 	e.w.Reset(w)
@@ -40,3 +48,9 @@ func (e *Encoder) Reset(w io.Writer) {
 func Marshal(schema Schema, v any) ([]byte, error) {
 	return DefaultConfig.Marshal(schema, v)
 }
+
+// AppendMarshal appends the Avro encoding of v to dst and returns the extended buffer.
+// If an error occurs, dst is returned unchanged.
+func AppendMarshal(schema Schema, dst []byte, v any) ([]byte, error) {
+	return DefaultConfig.AppendMarshal(schema, dst, v)
+}
@@ -50,3 +50,95 @@ func TestMarshal_Error(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestAppendMarshal(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("int")
+	dst := make([]byte, 1, 16)
+	dst[0] = 0xff
+
+	b, err := avro.AppendMarshal(schema, dst, 27)
+	require.NoError(t, err)
+	b, err = avro.AppendMarshal(schema, b, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0x36, 0x02}, b)
+	assert.Same(t, &dst[0], &b[0])
+}
+
+func TestAppendMarshal_Error(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("int")
+	dst := []byte{0xff}
+
+	b, err := avro.AppendMarshal(schema, dst, true)
+
+	assert.Error(t, err)
+	assert.Equal(t, []byte{0xff}, b)
+}
+
+func TestAppendMarshal_DoesNotAllocate(t *testing.T) {
+	defer ConfigTeardown()
+
+	type testObj struct {
+		A int64  `avro:"a"`
+		B string `avro:"b"`
+	}
+
+	schema := avro.MustParse(`{
+	"type": "record",
+	"name": "test",
+	"fields" : [
+		{"name": "a", "type": "long"},
+		{"name": "b", "type": "string"}
+	]
+}`)
+	obj := &testObj{A: 27, B: "foo"}
+	dst := make([]byte, 0, 64)
+	_, err := avro.AppendMarshal(schema, dst, obj)
+	require.NoError(t, err)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = avro.AppendMarshal(schema, dst[:0], obj)
+	})
+
+	assert.Zero(t, allocs)
+}
+
+func TestEncoder_AppendEncode(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("int")
+	buf := bytes.NewBuffer([]byte{})
+	enc := avro.NewEncoderForSchema(schema, buf)
+
+	b, err := enc.AppendEncode(nil, 27)
+	require.NoError(t, err)
+	b, err = enc.AppendEncode(b, 1)
+	require.NoError(t, err)
+	err = enc.Encode(13)
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x36, 0x02}, b)
+	assert.Equal(t, []byte{0x1a}, buf.Bytes())
+}
+
+func TestEncoder_AppendEncodeAfterError(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("int")
+	buf := bytes.NewBuffer([]byte{})
+	enc := avro.NewEncoderForSchema(schema, buf)
+
+	b, err := enc.AppendEncode([]byte{0xff}, "foo")
+	require.Error(t, err)
+	assert.Equal(t, []byte{0xff}, b)
+
+	b, err = enc.AppendEncode(b, 27)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0x36}, b)
+	require.NoError(t, enc.Encode(13))
+	assert.Equal(t, []byte{0x1a}, buf.Bytes())
+}
@@ -205,6 +205,42 @@ func ExampleMarshal() {
 	// Output: [54 6 102 111 111]
 }
 
+func ExampleNewTypedCodec() {
+	schema := avro.MustParse(`{
+	    "type": "record",
+	    "name": "simple",
+	    "namespace": "org.hamba.avro",
+	    "fields" : [
+	        {"name": "a", "type": "long"},
+	        {"name": "b", "type": "string"}
+	    ]
+	}`)
+
+	type SimpleRecord struct {
+		A int64  `avro:"a"`
+		B string `avro:"b"`
+	}
+
+	codec, err := avro.NewTypedCodec[SimpleRecord](schema)
+	if err != nil {
+		fmt.Println("error:", err)
+	}
+
+	b, err := codec.Marshal(SimpleRecord{A: 27, B: "foo"})
+	if err != nil {
+		fmt.Println("error:", err)
+	}
+
+	simple, err := codec.Unmarshal(b)
+	if err != nil {
+		fmt.Println("error:", err)
+	}
+
+	fmt.Println(b, simple)
+
+	// Output: [54 6 102 111 111] {27 foo}
+}
+
 func TestEncoderDecoder_Concurrency(t *testing.T) {
 	schema := avro.MustParse(`{
 	    "type": "record",
@@ -0,0 +1,65 @@
+package ocf
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hamba/avro/v2"
+)
+
+// NewAppendEncoder returns a new encoder that appends to the container file
+// in rw using schema s.
+//
+// See NewAppendEncoderWithSchema for details.
+func NewAppendEncoder(s string, rw io.ReadWriteSeeker, opts ...EncoderFunc) (*Encoder, error) {
+	cfg := computeEncoderConfig(opts)
+	schema, err := avro.ParseWithCache(s, "", cfg.SchemaCache)
+	if err != nil {
+		return nil, err
+	}
+	return newAppendEncoder(schema, rw, cfg)
+}
+
+// NewAppendEncoderWithSchema returns a new encoder that appends to the
+// container file in rw using schema.
+//
+// The existing header is read and its schema and codec must match schema and
+// the configured codec. New blocks are written at the end of the file using
+// the file's sync marker. If rw is empty, a new file is written.
+func NewAppendEncoderWithSchema(schema avro.Schema, rw io.ReadWriteSeeker, opts ...EncoderFunc) (*Encoder, error) {
+	return newAppendEncoder(schema, rw, computeEncoderConfig(opts))
+}
+
+func newAppendEncoder(schema avro.Schema, rw io.ReadWriteSeeker, cfg encoderConfig) (*Encoder, error) {
+	size, err := rw.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		return newEncoder(schema, rw, cfg)
+	}
+
+	if _, err = rw.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	reader := avro.NewReader(rw, 1024)
+	h, err := readHeader(reader, cfg.SchemaCache, cfg.CodecOptions)
+	if err != nil {
+		return nil, fmt.Errorf("encoder: %w", err)
+	}
+	if err = skipToEnd(reader, h.Sync); err != nil {
+		return nil, fmt.Errorf("encoder: %w", err)
+	}
+
+	if h.Schema.Fingerprint() != schema.Fingerprint() {
+		return nil, fmt.Errorf("encoder: file schema %s does not match schema %s", h.Schema, schema)
+	}
+	if name := codecName(h.Meta[codecKey]); name != codecName([]byte(cfg.CodecName)) {
+		return nil, fmt.Errorf("encoder: file codec %s does not match codec %s", name, cfg.CodecName)
+	}
+
+	if _, err = rw.Seek(0, io.SeekEnd); err != nil {
+		return nil, err
+	}
+	return newEncoderFromHeader(h, rw, cfg)
+}
@@ -0,0 +1,89 @@
+package ocf_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTempFile(t *testing.T, data []byte) *os.File {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.avro")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	return f
+}
+
+func TestNewAppendEncoder(t *testing.T) {
+	f := writeTempFile(t, encodeLongs(t, 3, ocf.WithCodec(ocf.Snappy)))
+
+	enc, err := ocf.NewAppendEncoder(`"long"`, f, ocf.WithCodec(ocf.Snappy))
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(int64(3)))
+	require.NoError(t, enc.Encode(int64(4)))
+	require.NoError(t, enc.Close())
+
+	data, err := os.ReadFile(f.Name())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0, 1, 2, 3, 4}, decodeLongs(t, data))
+}
+
+func TestNewAppendEncoder_ReusesSyncMarker(t *testing.T) {
+	sync := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	f := writeTempFile(t, encodeLongs(t, 3, ocf.WithSyncBlock(sync)))
+
+	enc, err := ocf.NewAppendEncoder(`"long"`, f)
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(int64(3)))
+	require.NoError(t, enc.Close())
+
+	data, err := os.ReadFile(f.Name())
+	require.NoError(t, err)
+	assert.Equal(t, sync[:], data[len(data)-16:])
+}
+
+func TestNewAppendEncoder_EmptyFile(t *testing.T) {
+	f := writeTempFile(t, nil)
+
+	enc, err := ocf.NewAppendEncoder(`"long"`, f)
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(int64(1)))
+	require.NoError(t, enc.Close())
+
+	data, err := os.ReadFile(f.Name())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, decodeLongs(t, data))
+}
+
+func TestNewAppendEncoder_SchemaMismatch(t *testing.T) {
+	f := writeTempFile(t, encodeLongs(t, 3))
+
+	_, err := ocf.NewAppendEncoder(`"string"`, f)
+
+	assert.Error(t, err)
+}
+
+func TestNewAppendEncoder_CodecMismatch(t *testing.T) {
+	f := writeTempFile(t, encodeLongs(t, 3, ocf.WithCodec(ocf.Deflate)))
+
+	_, err := ocf.NewAppendEncoder(`"long"`, f, ocf.WithCodec(ocf.Snappy))
+
+	assert.Error(t, err)
+}
+
+func TestNewAppendEncoder_TruncatedFile(t *testing.T) {
+	data := encodeLongs(t, 3)
+	f := writeTempFile(t, data[:len(data)-4])
+
+	_, err := ocf.NewAppendEncoder(`"long"`, f)
+
+	assert.Error(t, err)
+}
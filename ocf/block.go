@@ -0,0 +1,162 @@
+package ocf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hamba/avro/v2"
+)
+
+// Block is a data block of a container file.
+type Block struct {
+	// Offset is the byte offset of the block in the file.
+	Offset int64
+	// Count is the number of records in the block.
+	Count int64
+	// Data is the serialized records, compressed with Codec.
+	Data []byte
+	// Codec is the name of the codec Data is compressed with.
+	Codec CodecName
+}
+
+// BlockReader reads the raw data blocks of a container file, without
+// decompressing or decoding their records.
+type BlockReader struct {
+	dec   *Decoder
+	codec CodecName
+}
+
+// NewBlockReader returns a new block reader that reads from reader r.
+func NewBlockReader(r io.Reader, opts ...DecoderFunc) (*BlockReader, error) {
+	cfg := computeDecoderConfig(opts)
+	cfg.Concurrency = 0
+
+	dec, _, err := newOffsetDecoder(r, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockReader{
+		dec:   dec,
+		codec: codecName(dec.meta[codecKey]),
+	}, nil
+}
+
+// Metadata returns the header metadata.
+func (r *BlockReader) Metadata() map[string][]byte {
+	return r.dec.meta
+}
+
+// Schema returns the schema of the file.
+func (r *BlockReader) Schema() avro.Schema {
+	return r.dec.schema
+}
+
+// CodecName returns the name of the codec the blocks are compressed with.
+func (r *BlockReader) CodecName() CodecName {
+	return r.codec
+}
+
+// ReadBlock reads the next block. It returns io.EOF when there are no more blocks.
+func (r *BlockReader) ReadBlock() (*Block, error) {
+	offset := r.dec.offset
+	count, data, ok := r.dec.readRawBlock()
+	if !ok {
+		return nil, io.EOF
+	}
+	if err := r.dec.reader.Error; err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return &Block{Offset: offset, Count: count, Data: data, Codec: r.codec}, nil
+}
+
+// Decompress returns the serialized records of block b.
+func (r *BlockReader) Decompress(b *Block) ([]byte, error) {
+	if b.Codec != r.codec {
+		return nil, fmt.Errorf("block reader: block codec %s does not match file codec %s", b.Codec, r.codec)
+	}
+	return r.dec.codec.Decode(b.Data)
+}
+
+// Close releases codec resources.
+func (r *BlockReader) Close() error {
+	return r.dec.Close()
+}
+
+// BlockWriter writes raw data blocks to a container file, without encoding
+// or compressing their records.
+type BlockWriter struct {
+	enc   *Encoder
+	codec CodecName
+}
+
+// NewBlockWriter returns a new block writer that writes to w using schema.
+//
+// If the writer is an existing ocf file, it will append blocks using the
+// existing schema and codec.
+func NewBlockWriter(schema avro.Schema, w io.Writer, opts ...EncoderFunc) (*BlockWriter, error) {
+	cfg := computeEncoderConfig(opts)
+	cfg.Concurrency = 0
+
+	enc, err := newEncoder(schema, w, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockWriter{
+		enc:   enc,
+		codec: codecName(enc.header.Meta[codecKey]),
+	}, nil
+}
+
+// Schema returns the schema of the file.
+func (w *BlockWriter) Schema() avro.Schema {
+	return w.enc.schema
+}
+
+// CodecName returns the name of the codec the blocks are compressed with.
+func (w *BlockWriter) CodecName() CodecName {
+	return w.codec
+}
+
+// WriteBlock writes block b as is. The block must be compressed with the
+// codec of the file, and its records must conform to the schema of the file.
+func (w *BlockWriter) WriteBlock(b *Block) error {
+	if b.Codec != w.codec {
+		return fmt.Errorf("block writer: block codec %s does not match file codec %s", b.Codec, w.codec)
+	}
+	return w.writeBlock(b.Count, b.Data)
+}
+
+// WriteRecords compresses the serialized records in data and writes them as a
+// block of count records. The records must conform to the schema of the file.
+func (w *BlockWriter) WriteRecords(count int64, data []byte) error {
+	return w.writeBlock(count, w.enc.codec.Encode(data))
+}
+
+func (w *BlockWriter) writeBlock(count int64, data []byte) error {
+	writer := w.enc.writer
+	writer.WriteLong(count)
+	writer.WriteLong(int64(len(data)))
+	_, _ = writer.Write(data)
+	_, _ = writer.Write(w.enc.sync[:])
+	return writer.Flush()
+}
+
+// Close releases codec resources.
+func (w *BlockWriter) Close() error {
+	return w.enc.Close()
+}
+
+// codecName returns the codec name from the header metadata value.
+func codecName(b []byte) CodecName {
+	if len(b) == 0 {
+		return Null
+	}
+	return CodecName(b)
+}
@@ -0,0 +1,155 @@
+package ocf_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLongs(t *testing.T, data []byte) []int64 {
+	t.Helper()
+
+	dec, err := ocf.NewDecoder(bytes.NewReader(data))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = dec.Close() })
+
+	var got []int64
+	for dec.HasNext() {
+		var v int64
+		require.NoError(t, dec.Decode(&v))
+		got = append(got, v)
+	}
+	require.NoError(t, dec.Error())
+	return got
+}
+
+func TestBlockReader(t *testing.T) {
+	data := encodeLongs(t, 10, ocf.WithCodec(ocf.Snappy), ocf.WithBlockLength(4))
+
+	r, err := ocf.NewBlockReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = r.Close() })
+
+	assert.Equal(t, ocf.Snappy, r.CodecName())
+	assert.Equal(t, avro.Long, r.Schema().Type())
+
+	var counts []int64
+	for {
+		b, err := r.ReadBlock()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, ocf.Snappy, b.Codec)
+		counts = append(counts, b.Count)
+	}
+
+	assert.Equal(t, []int64{4, 4, 2}, counts)
+}
+
+func TestBlockReader_Truncated(t *testing.T) {
+	data := encodeLongs(t, 10, ocf.WithBlockLength(4))
+
+	r, err := ocf.NewBlockReader(bytes.NewReader(data[:len(data)-5]))
+	require.NoError(t, err)
+
+	var err2 error
+	for err2 == nil {
+		_, err2 = r.ReadBlock()
+	}
+
+	assert.NotErrorIs(t, err2, io.EOF)
+}
+
+func TestBlockWriter_Concat(t *testing.T) {
+	files := [][]byte{
+		encodeLongs(t, 5, ocf.WithCodec(ocf.Deflate), ocf.WithBlockLength(2)),
+		encodeLongs(t, 3, ocf.WithCodec(ocf.Deflate), ocf.WithBlockLength(2)),
+	}
+
+	buf := &bytes.Buffer{}
+	w, err := ocf.NewBlockWriter(avro.MustParse(`"long"`), buf, ocf.WithCodec(ocf.Deflate))
+	require.NoError(t, err)
+
+	for _, file := range files {
+		r, err := ocf.NewBlockReader(bytes.NewReader(file))
+		require.NoError(t, err)
+		for {
+			b, err := r.ReadBlock()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			require.NoError(t, w.WriteBlock(b))
+		}
+		require.NoError(t, r.Close())
+	}
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []int64{0, 1, 2, 3, 4, 0, 1, 2}, decodeLongs(t, buf.Bytes()))
+}
+
+func TestBlockWriter_WriteBlockCodecMismatch(t *testing.T) {
+	w, err := ocf.NewBlockWriter(avro.MustParse(`"long"`), &bytes.Buffer{}, ocf.WithCodec(ocf.Snappy))
+	require.NoError(t, err)
+
+	err = w.WriteBlock(&ocf.Block{Count: 1, Data: []byte{0x02}, Codec: ocf.Null})
+
+	assert.Error(t, err)
+}
+
+func TestBlockWriter_Recompress(t *testing.T) {
+	data := encodeLongs(t, 10, ocf.WithCodec(ocf.Snappy), ocf.WithBlockLength(4))
+
+	r, err := ocf.NewBlockReader(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	w, err := ocf.NewBlockWriter(r.Schema(), buf, ocf.WithCodec(ocf.ZStandard))
+	require.NoError(t, err)
+
+	for {
+		b, err := r.ReadBlock()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		records, err := r.Decompress(b)
+		require.NoError(t, err)
+		require.NoError(t, w.WriteRecords(b.Count, records))
+	}
+	require.NoError(t, r.Close())
+	require.NoError(t, w.Close())
+
+	dec, err := ocf.NewDecoder(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, []byte(ocf.ZStandard), dec.Metadata()["avro.codec"])
+	assert.Equal(t, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, decodeLongs(t, buf.Bytes()))
+}
+
+func TestBlockReader_Offsets(t *testing.T) {
+	data := encodeLongs(t, 10, ocf.WithBlockLength(4), ocf.WithSyncBlock(testSync))
+	syncs := syncOffsets(data)
+
+	r, err := ocf.NewBlockReader(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	var offsets []int64
+	for {
+		b, err := r.ReadBlock()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		offsets = append(offsets, b.Offset)
+	}
+
+	require.Len(t, syncs, 4)
+	assert.Equal(t, []int64{int64(syncs[0] + 16), int64(syncs[1] + 16), int64(syncs[2] + 16)}, offsets)
+}
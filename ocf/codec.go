@@ -2,12 +2,16 @@ package ocf
 
 import (
 	"bytes"
+	"compress/bzip2"
 	"compress/flate"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"hash/crc32"
 	"io"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/golang/snappy"
 	"github.com/klauspost/compress/zstd"
@@ -22,8 +26,61 @@ const (
 	Deflate   CodecName = "deflate"
 	Snappy    CodecName = "snappy"
 	ZStandard CodecName = "zstandard"
+	// Bzip2 is only supported for decoding.
+	Bzip2 CodecName = "bzip2"
 )
 
+// CodecFactory returns a new instance of a codec.
+type CodecFactory func() (Codec, error)
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[CodecName]CodecFactory{}
+)
+
+// RegisterCodec registers a codec factory under name, making the codec
+// available to encoders and decoders. Codecs are not required to be safe for
+// concurrent use, as each encoder and decoder creates its own instance.
+//
+// RegisterCodec is meant to be called from an init function. It panics if
+// factory is nil, or if a codec, including a built-in codec, is already
+// registered under name.
+func RegisterCodec(name CodecName, factory CodecFactory) {
+	if factory == nil {
+		panic("ocf: codec factory is nil")
+	}
+
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	if _, ok := codecs[name]; ok || isBuiltinCodec(name) {
+		panic("ocf: codec " + string(name) + " is already registered")
+	}
+	codecs[name] = factory
+}
+
+func isBuiltinCodec(name CodecName) bool {
+	switch name {
+	case Null, "", Deflate, Snappy, ZStandard, Bzip2:
+		return true
+	default:
+		return false
+	}
+}
+
+// registeredCodecs returns the names of all available codecs, sorted.
+func registeredCodecs() []string {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	names := []string{string(Null), string(Deflate), string(Snappy), string(ZStandard), string(Bzip2)}
+	for name := range codecs {
+		names = append(names, string(name))
+	}
+	slices.Sort(names)
+	return names
+}
+
 type codecOptions struct {
 	DeflateCompressionLevel int
 	ZStandardOptions        zstdOptions
@@ -52,15 +109,39 @@ func resolveCodec(name CodecName, codecOpts codecOptions) (Codec, error) {
 	case ZStandard:
 		return newZStandardCodec(codecOpts.ZStandardOptions), nil
 
+	case Bzip2:
+		return nil, fmt.Errorf("codec %s does not support encoding", Bzip2)
+
 	default:
-		return nil, fmt.Errorf("unknown codec %s", name)
+		codecsMu.RLock()
+		factory, ok := codecs[name]
+		codecsMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("unknown codec %s, registered codecs are: %s", name, strings.Join(registeredCodecs(), ", "))
+		}
+		return factory()
 	}
 }
 
-// Codec represents a compression codec.
-type Codec interface {
+// resolveDecompressor returns the codec of name for decoding, including codecs
+// that only support decoding.
+func resolveDecompressor(name CodecName, codecOpts codecOptions) (Decompressor, error) {
+	if name == Bzip2 {
+		return &Bzip2Codec{}, nil
+	}
+	return resolveCodec(name, codecOpts)
+}
+
+// Decompressor represents a compression codec that only supports decoding.
+type Decompressor interface {
 	// Decode decodes the given bytes.
 	Decode([]byte) ([]byte, error)
+}
+
+// Codec represents a compression codec.
+type Codec interface {
+	Decompressor
+
 	// Encode encodes the given bytes.
 	Encode([]byte) []byte
 }
@@ -140,6 +221,15 @@ func (*SnappyCodec) Encode(b []byte) []byte {
 	return dst
 }
 
+// Bzip2Codec is a bzip2 compression codec. It only supports decoding,
+// so it is a Decompressor rather than a Codec.
+type Bzip2Codec struct{}
+
+// Decode decodes the given bytes.
+func (*Bzip2Codec) Decode(b []byte) ([]byte, error) {
+	return io.ReadAll(bzip2.NewReader(bytes.NewReader(b)))
+}
+
 // ZStandardCodec is a zstandard compression codec.
 type ZStandardCodec struct {
 	decoder       *zstd.Decoder
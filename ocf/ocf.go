@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"os"
 
 	"github.com/hamba/avro/v2"
@@ -57,6 +58,11 @@ type decoderConfig struct {
 	DecoderConfig avro.API
 	SchemaCache   *avro.SchemaCache
 	CodecOptions  codecOptions
+	Concurrency   int
+	Unordered     bool
+	MaxInFlight   int
+	Recovery      func(*BlockError)
+	ReaderSchema  avro.Schema
 }
 
 // DecoderFunc represents a configuration function for Decoder.
@@ -93,6 +99,57 @@ func WithZStandardDecoder(dec *zstd.Decoder) DecoderFunc {
 	}
 }
 
+// WithDecoderConcurrency sets the number of workers decompressing blocks
+// ahead of the caller. Blocks are read sequentially, but decompressed
+// concurrently. The values of a TypedDecoder are also decoded by the workers.
+// A value of 1 or less disables concurrent decoding, which is the default.
+func WithDecoderConcurrency(workers int) DecoderFunc {
+	return func(cfg *decoderConfig) {
+		cfg.Concurrency = workers
+	}
+}
+
+// WithDecoderUnordered allows a concurrent decoder to yield blocks in the
+// order their decompression completes, rather than in file order.
+// This has no effect unless concurrent decoding is enabled.
+func WithDecoderUnordered() DecoderFunc {
+	return func(cfg *decoderConfig) {
+		cfg.Unordered = true
+	}
+}
+
+// WithDecoderMaxInFlightBlocks sets the maximum number of blocks held in
+// memory by a concurrent decoder, including the block being consumed.
+// It defaults to twice the number of workers.
+func WithDecoderMaxInFlightBlocks(n int) DecoderFunc {
+	return func(cfg *decoderConfig) {
+		cfg.MaxInFlight = n
+	}
+}
+
+// WithDecoderRecovery enables recovery from corrupted blocks.
+//
+// Instead of stopping at a corrupted block, the decoder reports it to fn and
+// scans forward to the next sync marker, resuming with the block that follows.
+// A value that fails to decode is still returned as an error by Decode, after
+// which the rest of its block is skipped. Recovery disables concurrent decoding.
+func WithDecoderRecovery(fn func(*BlockError)) DecoderFunc {
+	return func(cfg *decoderConfig) {
+		cfg.Recovery = fn
+	}
+}
+
+// WithReaderSchema sets the schema values are decoded with.
+//
+// The file schema is resolved against the reader schema following the Avro
+// schema resolution rules, failing if they are not compatible. Fields of the
+// file schema missing from the reader schema are skipped.
+func WithReaderSchema(schema avro.Schema) DecoderFunc {
+	return func(cfg *decoderConfig) {
+		cfg.ReaderSchema = schema
+	}
+}
+
 // Decoder reads and decodes Avro values from a container file.
 type Decoder struct {
 	reader      *avro.Reader
@@ -101,14 +158,35 @@ type Decoder struct {
 	meta        map[string][]byte
 	sync        [16]byte
 	schema      avro.Schema
+	readSchema  avro.Schema
 
-	codec Codec
+	codec Decompressor
 
 	count int64
+
+	// Byte range decoding.
+	offset int64
+	end    int64
+
+	// Recovery from corrupted blocks.
+	recovery    *recovery
+	blockOffset int64
+
+	// Concurrent decoding.
+	cfg         decoderConfig
+	pipeline    *blockPipeline
+	decodeBlock func(r *avro.Reader, count int64) any
+	values      any
+	blockCount  int64
+	err         error
 }
 
 // NewDecoder returns a new decoder that reads from reader r.
 func NewDecoder(r io.Reader, opts ...DecoderFunc) (*Decoder, error) {
+	return newDecoder(r, computeDecoderConfig(opts))
+}
+
+func computeDecoderConfig(opts []DecoderFunc) decoderConfig {
 	cfg := decoderConfig{
 		DecoderConfig: avro.DefaultConfig,
 		SchemaCache:   avro.DefaultSchemaCache,
@@ -119,6 +197,16 @@ func NewDecoder(r io.Reader, opts ...DecoderFunc) (*Decoder, error) {
 	for _, opt := range opts {
 		opt(&cfg)
 	}
+	if cfg.Recovery != nil {
+		cfg.Concurrency = 0
+	}
+	return cfg
+}
+
+func newDecoder(r io.Reader, cfg decoderConfig) (*Decoder, error) {
+	if cfg.Recovery != nil {
+		return newRecoveringDecoder(r, cfg)
+	}
 
 	reader := avro.NewReader(r, 1024)
 
@@ -127,16 +215,31 @@ func NewDecoder(r io.Reader, opts ...DecoderFunc) (*Decoder, error) {
 		return nil, fmt.Errorf("decoder: %w", err)
 	}
 
+	return newDecoderFromHeader(h, reader, cfg)
+}
+
+func newDecoderFromHeader(h *ocfHeader, reader *avro.Reader, cfg decoderConfig) (*Decoder, error) {
+	readSchema := h.Schema
+	if cfg.ReaderSchema != nil {
+		var err error
+		readSchema, err = avro.NewSchemaCompatibility().Resolve(cfg.ReaderSchema, h.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("decoder: %w", err)
+		}
+	}
+
 	decReader := bytesx.NewResetReader([]byte{})
 
 	return &Decoder{
 		reader:      reader,
 		resetReader: decReader,
-		decoder:     cfg.DecoderConfig.NewDecoder(h.Schema, decReader),
+		decoder:     cfg.DecoderConfig.NewDecoder(readSchema, decReader),
 		meta:        h.Meta,
 		sync:        h.Sync,
 		codec:       h.Codec,
 		schema:      h.Schema,
+		readSchema:  readSchema,
+		cfg:         cfg,
 	}, nil
 }
 
@@ -153,6 +256,10 @@ func (d *Decoder) Schema() avro.Schema {
 
 // HasNext determines if there is another value to read.
 func (d *Decoder) HasNext() bool {
+	if d.cfg.Concurrency > 1 {
+		return d.hasNextConcurrent()
+	}
+
 	if d.count <= 0 {
 		count := d.readBlock()
 		d.count = count
@@ -173,11 +280,75 @@ func (d *Decoder) Decode(v any) error {
 
 	d.count--
 
-	return d.decoder.Decode(v)
+	return d.recordError(d.decoder.Decode(v))
+}
+
+// DecodeGeneric reads the next Avro encoded value from its input as a generic value.
+// See avro.Reader.ReadNext for the types of the returned values.
+func (d *Decoder) DecodeGeneric() (any, error) {
+	if d.count <= 0 {
+		return nil, errors.New("decoder: no data found, call HasNext first")
+	}
+
+	d.count--
+
+	if d.cfg.ReaderSchema != nil {
+		// ReadNext does not apply schema resolution, the value decoders do.
+		var v any
+		err := d.decoder.Decode(&v)
+		return v, d.recordError(err)
+	}
+
+	v, err := d.decoder.DecodeGeneric()
+	return v, d.recordError(err)
+}
+
+// All returns an iterator over the values decoded from dec as type T.
+// Iteration stops at the end of the file, or after the first error is yielded.
+//
+// Breaking out of the loop leaves dec positioned after the last yielded value.
+// The caller remains responsible for closing dec.
+func All[T any](dec *Decoder) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for dec.HasNext() {
+			var v T
+			err := dec.Decode(&v)
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+		if err := dec.Error(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// AllGeneric returns an iterator over the values decoded from dec as generic values.
+// Iteration stops at the end of the file, or after the first error is yielded.
+//
+// Breaking out of the loop leaves dec positioned after the last yielded value.
+// The caller remains responsible for closing dec.
+func AllGeneric(dec *Decoder) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		for dec.HasNext() {
+			v, err := dec.DecodeGeneric()
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+		if err := dec.Error(); err != nil {
+			yield(nil, err)
+		}
+	}
 }
 
 // Error returns the last reader error.
 func (d *Decoder) Error() error {
+	if d.cfg.Concurrency > 1 {
+		return d.err
+	}
+
 	if errors.Is(d.reader.Error, io.EOF) {
 		return nil
 	}
@@ -187,6 +358,9 @@ func (d *Decoder) Error() error {
 
 // Close releases codec resources.
 func (d *Decoder) Close() error {
+	if d.pipeline != nil {
+		d.pipeline.stop()
+	}
 	if c, ok := d.codec.(io.Closer); ok {
 		return c.Close()
 	}
@@ -194,31 +368,52 @@ func (d *Decoder) Close() error {
 }
 
 func (d *Decoder) readBlock() int64 {
-	_ = d.reader.Peek()
-	if errors.Is(d.reader.Error, io.EOF) {
+	if d.recovery != nil {
+		count, data, ok := d.readRecoveredBlock()
+		if !ok {
+			return 0
+		}
+		d.resetReader.Reset(data)
+		return count
+	}
+
+	count, data, ok := d.readRawBlock()
+	if !ok {
 		// There is no next block
 		return 0
 	}
 
-	count := d.reader.ReadLong()
-	size := d.reader.ReadLong()
-
-	// Read the blocks data
-	switch {
-	case count > 0:
-		data := make([]byte, size)
-		d.reader.Read(data)
-
+	if count > 0 {
 		data, err := d.codec.Decode(data)
 		if err != nil {
 			d.reader.Error = err
 		}
 
 		d.resetReader.Reset(data)
+	}
+
+	return count
+}
+
+// readRawBlock reads the next block, returning its record count and its
+// compressed data. It returns false if there is no next block.
+func (d *Decoder) readRawBlock() (int64, []byte, bool) {
+	if d.pastRange() {
+		return 0, nil, false
+	}
+
+	_ = d.reader.Peek()
+	if errors.Is(d.reader.Error, io.EOF) {
+		return 0, nil, false
+	}
 
-	case size > 0:
-		// Skip the block data when count is 0
-		data := make([]byte, size)
+	count := d.reader.ReadLong()
+	size := d.reader.ReadLong()
+
+	// Read the blocks data
+	var data []byte
+	if count > 0 || size > 0 {
+		data = make([]byte, size)
 		d.reader.Read(data)
 	}
 
@@ -228,8 +423,9 @@ func (d *Decoder) readBlock() int64 {
 	if d.sync != sync && !errors.Is(d.reader.Error, io.EOF) {
 		d.reader.Error = errors.New("decoder: invalid block")
 	}
+	d.offset += longSize(count) + longSize(size) + int64(len(data)) + int64(len(sync))
 
-	return count
+	return count, data, true
 }
 
 type encoderConfig struct {
@@ -242,6 +438,8 @@ type encoderConfig struct {
 	EncodingConfig  avro.API
 	SchemaCache     *avro.SchemaCache
 	SchemaMarshaler func(avro.Schema) ([]byte, error)
+	Concurrency     int
+	MaxInFlight     int
 }
 
 // EncoderFunc represents a configuration function for Encoder.
@@ -339,11 +537,31 @@ func WithEncodingConfig(wCfg avro.API) EncoderFunc {
 	}
 }
 
+// WithEncoderConcurrency sets the number of workers compressing blocks.
+// Full blocks are handed to the workers, and written in order by a background
+// goroutine, so that encoding is not stalled by compression.
+// A value of 1 or less disables concurrent compression, which is the default.
+func WithEncoderConcurrency(workers int) EncoderFunc {
+	return func(cfg *encoderConfig) {
+		cfg.Concurrency = workers
+	}
+}
+
+// WithEncoderMaxInFlightBlocks sets the maximum number of blocks waiting to
+// be compressed or written by a concurrent encoder. Encoding blocks when the
+// limit is reached. It defaults to twice the number of workers.
+func WithEncoderMaxInFlightBlocks(n int) EncoderFunc {
+	return func(cfg *encoderConfig) {
+		cfg.MaxInFlight = n
+	}
+}
+
 // Encoder writes Avro container file to an output stream.
 type Encoder struct {
 	writer  *avro.Writer
 	buf     *bytes.Buffer
 	encoder *avro.Encoder
+	schema  avro.Schema
 	sync    [16]byte
 
 	codec Codec
@@ -354,6 +572,9 @@ type Encoder struct {
 
 	// Stored for Reset.
 	header Header
+	cfg    encoderConfig
+
+	pipeline *compressPipeline
 }
 
 // NewEncoder returns a new encoder that writes to w using schema s.
@@ -397,23 +618,7 @@ func newEncoder(schema avro.Schema, w io.Writer, cfg encoderConfig) (*Encoder, e
 				return nil, err
 			}
 
-			writer := avro.NewWriter(w, 512, avro.WithWriterConfig(cfg.EncodingConfig))
-			buf := &bytes.Buffer{}
-			e := &Encoder{
-				writer:      writer,
-				buf:         buf,
-				encoder:     cfg.EncodingConfig.NewEncoder(h.Schema, buf),
-				sync:        h.Sync,
-				codec:       h.Codec,
-				blockLength: cfg.BlockLength,
-				blockSize:   cfg.BlockSize,
-				header: Header{
-					Magic: magicBytes,
-					Meta:  h.Meta,
-					Sync:  h.Sync,
-				},
-			}
-			return e, nil
+			return newEncoderFromHeader(h, w, cfg)
 		}
 	}
 
@@ -449,11 +654,48 @@ func newEncoder(schema avro.Schema, w io.Writer, cfg encoderConfig) (*Encoder, e
 		writer:      writer,
 		buf:         buf,
 		encoder:     cfg.EncodingConfig.NewEncoder(schema, buf),
+		schema:      schema,
 		sync:        header.Sync,
 		codec:       codec,
 		blockLength: cfg.BlockLength,
 		blockSize:   cfg.BlockSize,
 		header:      header,
+		cfg:         cfg,
+	}
+	if err = e.startPipeline(cfg); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// newEncoderFromHeader returns an encoder that appends blocks to an existing
+// file with header h. The writer must be positioned at the end of the file.
+func newEncoderFromHeader(h *ocfHeader, w io.Writer, cfg encoderConfig) (*Encoder, error) {
+	codec, ok := h.Codec.(Codec)
+	if !ok {
+		return nil, fmt.Errorf("codec %s does not support encoding", h.Meta[codecKey])
+	}
+
+	writer := avro.NewWriter(w, 512, avro.WithWriterConfig(cfg.EncodingConfig))
+	buf := &bytes.Buffer{}
+	e := &Encoder{
+		writer:      writer,
+		buf:         buf,
+		encoder:     cfg.EncodingConfig.NewEncoder(h.Schema, buf),
+		schema:      h.Schema,
+		sync:        h.Sync,
+		codec:       codec,
+		blockLength: cfg.BlockLength,
+		blockSize:   cfg.BlockSize,
+		header: Header{
+			Magic: magicBytes,
+			Meta:  h.Meta,
+			Sync:  h.Sync,
+		},
+		cfg: cfg,
+	}
+	if err := e.startPipeline(cfg); err != nil {
+		return nil, err
 	}
 	return e, nil
 }
@@ -499,7 +741,7 @@ func (e *Encoder) Write(p []byte) (n int, err error) {
 		}
 	}
 
-	return n, e.writer.Error
+	return n, e.err()
 }
 
 // Encode writes the Avro encoding of v to the stream.
@@ -508,6 +750,12 @@ func (e *Encoder) Encode(v any) error {
 		return err
 	}
 
+	return e.encoded()
+}
+
+// encoded accounts for a value written to the internal buffer, writing
+// the block when it is full.
+func (e *Encoder) encoded() error {
 	e.count++
 	if e.shouldWriteDataBlock() {
 		if err := e.writerBlock(); err != nil {
@@ -515,11 +763,29 @@ func (e *Encoder) Encode(v any) error {
 		}
 	}
 
+	return e.err()
+}
+
+// err returns the last writer error.
+func (e *Encoder) err() error {
+	if e.pipeline != nil {
+		return e.pipeline.error()
+	}
 	return e.writer.Error
 }
 
-// Flush flushes the underlying writer.
+// Flush flushes the underlying writer. A concurrent encoder also waits for
+// all in-flight blocks to be written.
 func (e *Encoder) Flush() error {
+	if e.pipeline != nil {
+		if e.count > 0 {
+			if err := e.writerBlock(); err != nil {
+				return err
+			}
+		}
+		return e.pipeline.wait()
+	}
+
 	if e.count == 0 {
 		return nil
 	}
@@ -534,6 +800,9 @@ func (e *Encoder) Flush() error {
 // Close closes the encoder, flushing the writer and releasing codec resources.
 func (e *Encoder) Close() error {
 	err := e.Flush()
+	if e.pipeline != nil {
+		e.pipeline.stop()
+	}
 	if c, ok := e.codec.(io.Closer); ok {
 		if cerr := c.Close(); cerr != nil && err == nil {
 			err = cerr
@@ -546,8 +815,20 @@ func (e *Encoder) Close() error {
 // and writes a fresh header with a new sync marker. The schema, codec, and other
 // settings are preserved from the original encoder.
 // This allows reusing the encoder for multiple files without reallocating buffers.
+// If flushing fails, the error is returned and the pending data is discarded, so
+// that a following Reset starts the new file.
 func (e *Encoder) Reset(w io.Writer) error {
-	if err := e.Flush(); err != nil {
+	err := e.Flush()
+	if e.pipeline != nil {
+		// The pipeline is stopped by Close and keeps its first write error,
+		// so a fresh one is started for the new writer.
+		e.pipeline.stop()
+		e.pipeline = nil
+		if perr := e.startPipeline(e.cfg); perr != nil {
+			return perr
+		}
+	}
+	if err != nil {
 		return err
 	}
 
@@ -557,6 +838,7 @@ func (e *Encoder) Reset(w io.Writer) error {
 
 	// Reset writer to new output and write header.
 	e.writer.Reset(w)
+	e.writer.Error = nil
 	e.writer.WriteVal(HeaderSchema, e.header)
 	if err := e.writer.Flush(); err != nil {
 		return err
@@ -571,6 +853,13 @@ func (e *Encoder) Reset(w io.Writer) error {
 }
 
 func (e *Encoder) writerBlock() error {
+	if e.pipeline != nil {
+		err := e.pipeline.submit(e.count, bytes.Clone(e.buf.Bytes()), e.sync)
+		e.count = 0
+		e.buf.Reset()
+		return err
+	}
+
 	e.writer.WriteLong(int64(e.count))
 
 	b := e.codec.Encode(e.buf.Bytes())
@@ -587,7 +876,7 @@ func (e *Encoder) writerBlock() error {
 
 type ocfHeader struct {
 	Schema avro.Schema
-	Codec  Codec
+	Codec  Decompressor
 	Meta   map[string][]byte
 	Sync   [16]byte
 }
@@ -607,7 +896,7 @@ func readHeader(reader *avro.Reader, schemaCache *avro.SchemaCache, codecOpts co
 		return nil, err
 	}
 
-	codec, err := resolveCodec(CodecName(h.Meta[codecKey]), codecOpts)
+	codec, err := resolveDecompressor(CodecName(h.Meta[codecKey]), codecOpts)
 	if err != nil {
 		return nil, err
 	}
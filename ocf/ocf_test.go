@@ -398,6 +398,102 @@ func TestDecoder_WithZStandard(t *testing.T) {
 	assert.Equal(t, 1, count)
 }
 
+func TestDecoder_WithBzip2(t *testing.T) {
+	decodeFile := func(name string) []FullRecord {
+		f, err := os.Open(name)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = f.Close() })
+
+		dec, err := ocf.NewDecoder(f)
+		require.NoError(t, err)
+
+		var got []FullRecord
+		for dec.HasNext() {
+			var rec FullRecord
+			require.NoError(t, dec.Decode(&rec))
+			got = append(got, rec)
+		}
+		require.NoError(t, dec.Error())
+		return got
+	}
+
+	got := decodeFile("testdata/full-bzip2.avro")
+
+	assert.Len(t, got, 1)
+	assert.Equal(t, decodeFile("testdata/full.avro"), got)
+}
+
+func TestNewEncoder_Bzip2NotSupported(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	_, err := ocf.NewEncoder(`"long"`, buf, ocf.WithCodec(ocf.Bzip2))
+
+	assert.Error(t, err)
+}
+
+func TestNewAppendEncoder_Bzip2NotSupported(t *testing.T) {
+	data, err := os.ReadFile("testdata/full-bzip2.avro")
+	require.NoError(t, err)
+	dec, err := ocf.NewDecoder(bytes.NewReader(data))
+	require.NoError(t, err)
+	f := writeTempFile(t, data)
+
+	_, err = ocf.NewAppendEncoder(dec.Schema().String(), f, ocf.WithCodec(ocf.Bzip2))
+
+	assert.ErrorContains(t, err, "codec bzip2 does not support encoding")
+}
+
+type xorCodec struct{}
+
+func (xorCodec) Decode(b []byte) ([]byte, error) {
+	return xorCodec{}.Encode(b), nil
+}
+
+func (xorCodec) Encode(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i, c := range b {
+		out[i] = c ^ 0x5a
+	}
+	return out
+}
+
+func TestRegisterCodec(t *testing.T) {
+	name := ocf.CodecName("test-xor")
+	ocf.RegisterCodec(name, func() (ocf.Codec, error) { return xorCodec{}, nil })
+
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"long"`, buf, ocf.WithCodec(name))
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(int64(27)))
+	require.NoError(t, enc.Close())
+
+	dec, err := ocf.NewDecoder(buf)
+	require.NoError(t, err)
+
+	require.True(t, dec.HasNext())
+	var got int64
+	require.NoError(t, dec.Decode(&got))
+	assert.Equal(t, int64(27), got)
+	assert.Equal(t, []byte(name), dec.Metadata()["avro.codec"])
+}
+
+func TestRegisterCodec_PanicsOnDuplicate(t *testing.T) {
+	factory := func() (ocf.Codec, error) { return xorCodec{}, nil }
+	ocf.RegisterCodec("test-duplicate", factory)
+
+	assert.Panics(t, func() { ocf.RegisterCodec("test-duplicate", factory) })
+	assert.Panics(t, func() { ocf.RegisterCodec(ocf.Snappy, factory) })
+	assert.Panics(t, func() { ocf.RegisterCodec("test-nil", nil) })
+}
+
+func TestNewEncoder_UnknownCodecListsRegisteredCodecs(t *testing.T) {
+	_, err := ocf.NewEncoder(`"long"`, &bytes.Buffer{}, ocf.WithCodec("xz"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown codec xz")
+	assert.Contains(t, err.Error(), "bzip2, deflate, null, snappy")
+}
+
 func TestDecoder_WithZStandardHandlesInvalidData(t *testing.T) {
 	f, err := os.Open("testdata/zstd-invalid-data.avro")
 	require.NoError(t, err)
@@ -1649,3 +1745,92 @@ func TestEncoder_ResetPreservesCodec(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []byte("deflate"), dec2.Metadata()["avro.codec"])
 }
+
+func TestAll(t *testing.T) {
+	f, err := os.Open("testdata/full.avro")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	dec, err := ocf.NewDecoder(f)
+	require.NoError(t, err)
+
+	var count int
+	for rec, err := range ocf.All[FullRecord](dec) {
+		require.NoError(t, err)
+		assert.Equal(t, "I am a test record", rec.Record.String)
+		count++
+	}
+
+	assert.Equal(t, 1, count)
+}
+
+func TestAll_Break(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"long"`, buf, ocf.WithBlockLength(2))
+	require.NoError(t, err)
+	for i := range 5 {
+		require.NoError(t, enc.Encode(int64(i)))
+	}
+	require.NoError(t, enc.Close())
+
+	dec, err := ocf.NewDecoder(buf)
+	require.NoError(t, err)
+
+	var got []int64
+	for v, err := range ocf.All[int64](dec) {
+		require.NoError(t, err)
+		got = append(got, v)
+		if len(got) == 3 {
+			break
+		}
+	}
+	for v, err := range ocf.All[int64](dec) {
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+
+	assert.Equal(t, []int64{0, 1, 2, 3, 4}, got)
+}
+
+func TestAll_InvalidBlock(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"long"`, buf, ocf.WithSyncBlock([16]byte{1}))
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(int64(1)))
+	require.NoError(t, enc.Close())
+
+	// Corrupt the sync marker of the block.
+	data := buf.Bytes()
+	data[len(data)-1] = 0xff
+
+	dec, err := ocf.NewDecoder(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	var errs []error
+	for _, err := range ocf.All[int64](dec) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	assert.Error(t, errs[0])
+}
+
+func TestAllGeneric(t *testing.T) {
+	f, err := os.Open("testdata/full.avro")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	dec, err := ocf.NewDecoder(f)
+	require.NoError(t, err)
+
+	var got []any
+	for rec, err := range ocf.AllGeneric(dec) {
+		require.NoError(t, err)
+		got = append(got, rec)
+	}
+
+	require.Len(t, got, 1)
+	rec := got[0].(map[string]any)
+	assert.Equal(t, "C", rec["enum"])
+	assert.Equal(t, "I am a test record", rec["record"].(map[string]any)["string"])
+}
@@ -0,0 +1,402 @@
+package ocf
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/hamba/avro/v2"
+)
+
+// pipelineBlock is a block moving through a blockPipeline.
+type pipelineBlock struct {
+	count  int64
+	data   []byte
+	values any
+	err    error
+
+	ready chan struct{}
+}
+
+// blockPipeline reads blocks sequentially and decompresses them concurrently.
+type blockPipeline struct {
+	ordered chan *pipelineBlock // Blocks in file order, in ordered mode.
+	results chan *pipelineBlock // Blocks in completion order, in unordered mode.
+	tokens  chan struct{}       // Bounds the blocks in flight.
+	quit    chan struct{}
+
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+func (d *Decoder) startPipeline() error {
+	workers := d.cfg.Concurrency
+
+	// Codecs are not safe for concurrent use, so each worker gets its own.
+	codecs := make([]Decompressor, 0, workers)
+	for range workers {
+		codec, err := resolveDecompressor(CodecName(d.meta[codecKey]), d.cfg.CodecOptions)
+		if err != nil {
+			return err
+		}
+		codecs = append(codecs, codec)
+	}
+
+	maxInFlight := d.cfg.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = 2 * workers
+	}
+
+	p := &blockPipeline{
+		tokens: make(chan struct{}, maxInFlight),
+		quit:   make(chan struct{}),
+	}
+	if d.cfg.Unordered {
+		p.results = make(chan *pipelineBlock, maxInFlight)
+	} else {
+		p.ordered = make(chan *pipelineBlock, maxInFlight)
+	}
+
+	jobs := make(chan *pipelineBlock, maxInFlight)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		d.readBlocks(p, jobs)
+	}()
+
+	var workerWG sync.WaitGroup
+	workerWG.Add(workers)
+	for _, codec := range codecs {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			defer workerWG.Done()
+			d.processBlocks(p, codec, jobs)
+		}()
+	}
+
+	if p.results != nil {
+		go func() {
+			workerWG.Wait()
+			close(p.results)
+		}()
+	}
+
+	d.pipeline = p
+	return nil
+}
+
+// readBlocks reads the raw blocks and hands them to the workers. It is the
+// only user of the underlying reader once the pipeline is started.
+func (d *Decoder) readBlocks(p *blockPipeline, jobs chan<- *pipelineBlock) {
+	defer close(jobs)
+	if p.ordered != nil {
+		defer close(p.ordered)
+	}
+
+	for {
+		select {
+		case p.tokens <- struct{}{}:
+		case <-p.quit:
+			return
+		}
+
+		count, data, ok := d.readRawBlock()
+		if !ok {
+			<-p.tokens
+			return
+		}
+
+		blk := &pipelineBlock{count: count, data: data, ready: make(chan struct{})}
+		if err := d.reader.Error; err != nil && !errors.Is(err, io.EOF) {
+			blk.err = err
+		} else if count <= 0 {
+			// Blocks without records do not need processing.
+			<-p.tokens
+			continue
+		}
+
+		if p.ordered != nil {
+			select {
+			case p.ordered <- blk:
+			case <-p.quit:
+				return
+			}
+		}
+		select {
+		case jobs <- blk:
+		case <-p.quit:
+			return
+		}
+
+		if blk.err != nil {
+			return
+		}
+	}
+}
+
+// processBlocks decompresses, and optionally decodes, blocks until there are
+// no more jobs.
+func (d *Decoder) processBlocks(p *blockPipeline, codec Decompressor, jobs <-chan *pipelineBlock) {
+	if c, ok := codec.(io.Closer); ok {
+		defer func() { _ = c.Close() }()
+	}
+
+	var reader *avro.Reader
+	if d.decodeBlock != nil {
+		reader = avro.NewReader(nil, 0, avro.WithReaderConfig(d.cfg.DecoderConfig))
+	}
+
+	for blk := range jobs {
+		if blk.err == nil {
+			d.processBlock(codec, reader, blk)
+		}
+
+		if p.results == nil {
+			close(blk.ready)
+			continue
+		}
+		select {
+		case p.results <- blk:
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+func (d *Decoder) processBlock(codec Decompressor, reader *avro.Reader, blk *pipelineBlock) {
+	data, err := codec.Decode(blk.data)
+	if err != nil {
+		blk.err = err
+		return
+	}
+	blk.data = data
+
+	if reader == nil {
+		return
+	}
+
+	reader.Reset(data)
+	blk.values = d.decodeBlock(reader, blk.count)
+	if err = reader.Error; err != nil && !errors.Is(err, io.EOF) {
+		blk.err = err
+	}
+	reader.Error = nil
+}
+
+// next returns the next processed block, or false if there are no more blocks.
+func (p *blockPipeline) next() (*pipelineBlock, bool) {
+	var (
+		blk *pipelineBlock
+		ok  bool
+	)
+	if p.ordered != nil {
+		blk, ok = <-p.ordered
+		if ok {
+			select {
+			case <-blk.ready:
+			case <-p.quit:
+				return nil, false
+			}
+		}
+	} else {
+		blk, ok = <-p.results
+	}
+	if ok {
+		<-p.tokens
+	}
+	return blk, ok
+}
+
+// stop stops the pipeline and waits for its goroutines to exit.
+func (p *blockPipeline) stop() {
+	p.stopOnce.Do(func() {
+		close(p.quit)
+		p.wg.Wait()
+	})
+}
+
+func (d *Decoder) hasNextConcurrent() bool {
+	if d.count > 0 {
+		return true
+	}
+	if d.err != nil {
+		return false
+	}
+	if d.pipeline == nil {
+		if err := d.startPipeline(); err != nil {
+			d.err = err
+			return false
+		}
+	}
+
+	blk, ok := d.pipeline.next()
+	if !ok {
+		d.values = nil
+		return false
+	}
+	if blk.err != nil {
+		d.err = blk.err
+		d.pipeline.stop()
+		return false
+	}
+
+	d.resetReader.Reset(blk.data)
+	d.values = blk.values
+	d.blockCount = blk.count
+	d.count = blk.count
+	return true
+}
+
+// compressJob is a block moving through a compressPipeline.
+type compressJob struct {
+	count int
+	data  []byte
+	sync  [16]byte
+
+	ready chan struct{}
+}
+
+// compressPipeline compresses blocks concurrently and writes them in order.
+type compressPipeline struct {
+	jobs    chan *compressJob
+	ordered chan *compressJob // Bounds the blocks in flight.
+
+	pending sync.WaitGroup // Blocks not yet written.
+	wg      sync.WaitGroup
+
+	mu      sync.Mutex
+	err     error
+	stopped bool
+
+	stopOnce sync.Once
+}
+
+func (e *Encoder) startPipeline(cfg encoderConfig) error {
+	workers := cfg.Concurrency
+	if workers <= 1 {
+		return nil
+	}
+
+	// Codecs are not safe for concurrent use, so each worker gets its own.
+	codecs := make([]Codec, 0, workers)
+	for range workers {
+		codec, err := resolveCodec(CodecName(e.header.Meta[codecKey]), cfg.CodecOptions)
+		if err != nil {
+			return err
+		}
+		codecs = append(codecs, codec)
+	}
+
+	maxInFlight := cfg.MaxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = 2 * workers
+	}
+
+	p := &compressPipeline{
+		jobs:    make(chan *compressJob, maxInFlight),
+		ordered: make(chan *compressJob, maxInFlight),
+	}
+
+	for _, codec := range codecs {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.compress(codec)
+		}()
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.write(e.writer)
+	}()
+
+	e.pipeline = p
+	return nil
+}
+
+// submit hands a block to the pipeline, blocking while the maximum number of
+// blocks are in flight.
+func (p *compressPipeline) submit(count int, data []byte, syncMarker [16]byte) error {
+	p.mu.Lock()
+	err, stopped := p.err, p.stopped
+	p.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if stopped {
+		return errors.New("encoder: closed")
+	}
+
+	job := &compressJob{count: count, data: data, sync: syncMarker, ready: make(chan struct{})}
+	p.pending.Add(1)
+	p.ordered <- job
+	p.jobs <- job
+	return nil
+}
+
+func (p *compressPipeline) compress(codec Codec) {
+	if c, ok := codec.(io.Closer); ok {
+		defer func() { _ = c.Close() }()
+	}
+
+	for job := range p.jobs {
+		job.data = codec.Encode(job.data)
+		close(job.ready)
+	}
+}
+
+func (p *compressPipeline) write(writer *avro.Writer) {
+	for job := range p.ordered {
+		<-job.ready
+
+		if p.error() == nil {
+			writer.WriteLong(int64(job.count))
+			writer.WriteLong(int64(len(job.data)))
+			_, _ = writer.Write(job.data)
+			_, _ = writer.Write(job.sync[:])
+			if err := writer.Flush(); err != nil {
+				p.setError(err)
+			}
+		}
+
+		p.pending.Done()
+	}
+}
+
+// wait waits for all submitted blocks to be written.
+func (p *compressPipeline) wait() error {
+	p.pending.Wait()
+	return p.error()
+}
+
+// stop stops the pipeline once all submitted blocks are written.
+func (p *compressPipeline) stop() {
+	p.stopOnce.Do(func() {
+		p.mu.Lock()
+		p.stopped = true
+		p.mu.Unlock()
+
+		close(p.jobs)
+		close(p.ordered)
+		p.wg.Wait()
+	})
+}
+
+func (p *compressPipeline) error() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+func (p *compressPipeline) setError(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err == nil {
+		p.err = err
+	}
+}
@@ -0,0 +1,282 @@
+package ocf_test
+
+import (
+	"bytes"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeLongs(t *testing.T, n int, opts ...ocf.EncoderFunc) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"long"`, buf, opts...)
+	require.NoError(t, err)
+	for i := range n {
+		require.NoError(t, enc.Encode(int64(i)))
+	}
+	require.NoError(t, enc.Close())
+	return buf.Bytes()
+}
+
+func TestDecoder_Concurrent(t *testing.T) {
+	codecs := []ocf.CodecName{ocf.Null, ocf.Deflate, ocf.Snappy, ocf.ZStandard}
+
+	for _, codec := range codecs {
+		t.Run(string(codec), func(t *testing.T) {
+			data := encodeLongs(t, 1000, ocf.WithCodec(codec), ocf.WithBlockLength(7))
+
+			dec, err := ocf.NewDecoder(bytes.NewReader(data), ocf.WithDecoderConcurrency(4))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = dec.Close() })
+
+			var got []int64
+			for dec.HasNext() {
+				var v int64
+				require.NoError(t, dec.Decode(&v))
+				got = append(got, v)
+			}
+
+			require.NoError(t, dec.Error())
+			require.Len(t, got, 1000)
+			for i, v := range got {
+				assert.Equal(t, int64(i), v)
+			}
+		})
+	}
+}
+
+func TestDecoder_ConcurrentUnordered(t *testing.T) {
+	data := encodeLongs(t, 1000, ocf.WithCodec(ocf.Deflate), ocf.WithBlockLength(7))
+
+	dec, err := ocf.NewDecoder(bytes.NewReader(data),
+		ocf.WithDecoderConcurrency(4),
+		ocf.WithDecoderUnordered(),
+		ocf.WithDecoderMaxInFlightBlocks(3),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = dec.Close() })
+
+	var got []int64
+	for v, err := range ocf.All[int64](dec) {
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+
+	slices.Sort(got)
+	require.Len(t, got, 1000)
+	for i, v := range got {
+		assert.Equal(t, int64(i), v)
+	}
+}
+
+func TestDecoder_ConcurrentInvalidBlock(t *testing.T) {
+	data := encodeLongs(t, 20, ocf.WithBlockLength(7), ocf.WithSyncBlock([16]byte{1}))
+
+	// Corrupt the sync marker of the last block.
+	data[len(data)-1] = 0xff
+
+	dec, err := ocf.NewDecoder(bytes.NewReader(data), ocf.WithDecoderConcurrency(2))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = dec.Close() })
+
+	var count int
+	for dec.HasNext() {
+		var v int64
+		require.NoError(t, dec.Decode(&v))
+		count++
+	}
+
+	assert.Error(t, dec.Error())
+	assert.Equal(t, 14, count)
+}
+
+func TestDecoder_ConcurrentCloseEarly(t *testing.T) {
+	data := encodeLongs(t, 1000, ocf.WithBlockLength(7))
+
+	dec, err := ocf.NewDecoder(bytes.NewReader(data), ocf.WithDecoderConcurrency(4), ocf.WithDecoderMaxInFlightBlocks(2))
+	require.NoError(t, err)
+
+	require.True(t, dec.HasNext())
+	require.NoError(t, dec.Close())
+}
+
+func TestTypedDecoder_Concurrent(t *testing.T) {
+	type record struct {
+		A int64  `avro:"a"`
+		B string `avro:"b"`
+	}
+	schema := avro.MustParse(`{"type":"record","name":"test","fields":[{"name":"a","type":"long"},{"name":"b","type":"string"}]}`)
+
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewTypedEncoder[record](schema, buf, ocf.WithBlockLength(3), ocf.WithCodec(ocf.Snappy))
+	require.NoError(t, err)
+	for i := range 100 {
+		require.NoError(t, enc.Encode(record{A: int64(i), B: "foo"}))
+	}
+	require.NoError(t, enc.Close())
+
+	dec, err := ocf.NewTypedDecoder[record](buf, ocf.WithDecoderConcurrency(3))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = dec.Close() })
+
+	var i int64
+	for rec, err := range dec.All() {
+		require.NoError(t, err)
+		assert.Equal(t, record{A: i, B: "foo"}, rec)
+		i++
+	}
+	assert.Equal(t, int64(100), i)
+}
+
+func TestEncoder_Concurrent(t *testing.T) {
+	codecs := []ocf.CodecName{ocf.Null, ocf.Deflate, ocf.Snappy, ocf.ZStandard}
+
+	for _, codec := range codecs {
+		t.Run(string(codec), func(t *testing.T) {
+			data := encodeLongs(t, 1000,
+				ocf.WithCodec(codec),
+				ocf.WithBlockLength(7),
+				ocf.WithEncoderConcurrency(4),
+				ocf.WithEncoderMaxInFlightBlocks(3),
+			)
+
+			dec, err := ocf.NewDecoder(bytes.NewReader(data))
+			require.NoError(t, err)
+
+			var got []int64
+			for v, err := range ocf.All[int64](dec) {
+				require.NoError(t, err)
+				got = append(got, v)
+			}
+
+			require.Len(t, got, 1000)
+			for i, v := range got {
+				assert.Equal(t, int64(i), v)
+			}
+		})
+	}
+}
+
+func TestEncoder_ConcurrentMatchesSequential(t *testing.T) {
+	want := encodeLongs(t, 100, ocf.WithCodec(ocf.Snappy), ocf.WithBlockLength(7), ocf.WithSyncBlock([16]byte{1}))
+
+	got := encodeLongs(t, 100,
+		ocf.WithCodec(ocf.Snappy),
+		ocf.WithBlockLength(7),
+		ocf.WithSyncBlock([16]byte{1}),
+		ocf.WithEncoderConcurrency(3),
+	)
+
+	// Header metadata order is not deterministic, so compare from the header sync marker.
+	sync := []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	assert.Equal(t, want[bytes.Index(want, sync):], got[bytes.Index(got, sync):])
+}
+
+func TestEncoder_ConcurrentFlushWaitsForBlocks(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"long"`, buf, ocf.WithBlockLength(2), ocf.WithEncoderConcurrency(2))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = enc.Close() })
+
+	for i := range 5 {
+		require.NoError(t, enc.Encode(int64(i)))
+	}
+	require.NoError(t, enc.Flush())
+
+	dec, err := ocf.NewDecoder(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	var count int
+	for _, err := range ocf.All[int64](dec) {
+		require.NoError(t, err)
+		count++
+	}
+	assert.Equal(t, 5, count)
+}
+
+func TestEncoder_ConcurrentCloseResetEncode(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"long"`, buf, ocf.WithBlockLength(2), ocf.WithEncoderConcurrency(2))
+	require.NoError(t, err)
+
+	require.NoError(t, enc.Encode(int64(1)))
+	require.NoError(t, enc.Close())
+
+	buf2 := &bytes.Buffer{}
+	require.NoError(t, enc.Reset(buf2))
+	for i := range 3 {
+		require.NoError(t, enc.Encode(int64(i)))
+	}
+	require.NoError(t, enc.Close())
+
+	assert.Equal(t, []int64{1}, decodeLongs(t, buf.Bytes()))
+	assert.Equal(t, []int64{0, 1, 2}, decodeLongs(t, buf2.Bytes()))
+}
+
+func TestEncoder_ConcurrentResetClearsWriteError(t *testing.T) {
+	w := &errorBlockWriter{}
+	enc, err := ocf.NewEncoder(`"long"`, w, ocf.WithBlockLength(1), ocf.WithEncoderConcurrency(2))
+	require.NoError(t, err)
+	for i := range 5 {
+		_ = enc.Encode(int64(i))
+	}
+	require.Error(t, enc.Flush())
+
+	buf := &bytes.Buffer{}
+	require.Error(t, enc.Reset(buf))
+	require.NoError(t, enc.Reset(buf))
+	require.NoError(t, enc.Encode(int64(7)))
+	require.NoError(t, enc.Close())
+
+	assert.Equal(t, []int64{7}, decodeLongs(t, buf.Bytes()))
+}
+
+func TestEncoder_ConcurrentWriteError(t *testing.T) {
+	w := &errorBlockWriter{}
+	enc, err := ocf.NewEncoder(`"long"`, w, ocf.WithBlockLength(1), ocf.WithEncoderConcurrency(2))
+	require.NoError(t, err)
+
+	for i := range 5 {
+		_ = enc.Encode(int64(i))
+	}
+
+	assert.Error(t, enc.Close())
+}
+
+func TestTypedDecoder_ConcurrentLimitsPerValue(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewTypedEncoder[string](avro.MustParse(`"string"`), buf, ocf.WithBlockLength(100))
+	require.NoError(t, err)
+	for range 100 {
+		require.NoError(t, enc.Encode(strings.Repeat("a", 100)))
+	}
+	require.NoError(t, enc.Close())
+
+	cfg := avro.Config{MaxTotalAllocation: 1000}.Freeze()
+	for _, concurrency := range []int{1, 4} {
+		t.Run(strconv.Itoa(concurrency), func(t *testing.T) {
+			dec, err := ocf.NewTypedDecoder[string](bytes.NewReader(buf.Bytes()),
+				ocf.WithDecoderConfig(cfg),
+				ocf.WithDecoderConcurrency(concurrency),
+			)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = dec.Close() })
+
+			var n int
+			for s, err := range dec.All() {
+				require.NoError(t, err)
+				assert.Len(t, s, 100)
+				n++
+			}
+			assert.Equal(t, 100, n)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package ocf
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hamba/avro/v2"
+)
+
+// NewRangeDecoder returns a new decoder that reads the blocks of the container
+// file in r that belong to the byte range [start, end).
+//
+// The header is read from the start of the file, after which the decoder seeks
+// to the first sync marker at or after start. A block belongs to the range
+// when the sync marker preceding it starts within the range, so the last
+// block read may cross end. Splitting a file into adjacent ranges therefore
+// reads every block exactly once.
+func NewRangeDecoder(r io.ReaderAt, size, start, end int64, opts ...DecoderFunc) (*Decoder, error) {
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("decoder: invalid range [%d, %d) for size %d", start, end, size)
+	}
+
+	dec, err := newDecoder(io.NewSectionReader(r, 0, size), computeDecoderConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	pos, err := findSync(r, size, start, dec.sync)
+	if err != nil {
+		return nil, fmt.Errorf("decoder: %w", err)
+	}
+	if pos < 0 || pos >= end {
+		// The range holds no block, start reading at the end of the file.
+		pos = size - syncSize
+	}
+
+	dec.offset = pos + syncSize
+	dec.end = end
+	blocks := io.NewSectionReader(r, dec.offset, size-dec.offset)
+	dec.reader = avro.NewReader(blocks, 1024)
+	if dec.recovery != nil {
+		dec.recovery.src = bufio.NewReader(blocks)
+	}
+
+	return dec, nil
+}
+
+const syncSize = 16
+
+// findSync returns the offset of the first sync marker in r at or after
+// from, or -1 if there is none.
+func findSync(r io.ReaderAt, size, from int64, sync [16]byte) (int64, error) {
+	const chunkSize = 64 * 1024
+
+	buf := make([]byte, chunkSize+syncSize-1)
+	for off := from; off <= size-syncSize; off += chunkSize {
+		n, err := r.ReadAt(buf[:min(int64(len(buf)), size-off)], off)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+		if i := bytes.Index(buf[:n], sync[:]); i >= 0 {
+			return off + int64(i), nil
+		}
+	}
+	return -1, nil
+}
+
+// pastRange reports if the decoder has read all the blocks in its byte range.
+func (d *Decoder) pastRange() bool {
+	return d.end > 0 && d.offset-syncSize >= d.end
+}
+
+// longSize returns the size of v encoded as an Avro long.
+func longSize(v int64) int64 {
+	u := uint64((v << 1) ^ (v >> 63))
+	n := int64(1)
+	for u >= 0x80 {
+		u >>= 7
+		n++
+	}
+	return n
+}
@@ -0,0 +1,95 @@
+package ocf_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readRange(t *testing.T, data []byte, start, end int64, opts ...ocf.DecoderFunc) []int64 {
+	t.Helper()
+
+	dec, err := ocf.NewRangeDecoder(bytes.NewReader(data), int64(len(data)), start, end, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = dec.Close() })
+
+	var got []int64
+	for dec.HasNext() {
+		var v int64
+		require.NoError(t, dec.Decode(&v))
+		got = append(got, v)
+	}
+	require.NoError(t, dec.Error())
+	return got
+}
+
+func TestNewRangeDecoder_WholeFile(t *testing.T) {
+	data := encodeLongs(t, 100, ocf.WithBlockLength(7))
+
+	got := readRange(t, data, 0, int64(len(data)))
+
+	want := make([]int64, 100)
+	for i := range want {
+		want[i] = int64(i)
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestNewRangeDecoder_Splits(t *testing.T) {
+	data := encodeLongs(t, 1000, ocf.WithCodec(ocf.Deflate), ocf.WithBlockLength(13))
+	size := int64(len(data))
+
+	for _, splitSize := range []int64{1, 17, 100, 333, size} {
+		var got []int64
+		for start := int64(0); start < size; start += splitSize {
+			got = append(got, readRange(t, data, start, min(start+splitSize, size))...)
+		}
+
+		require.Len(t, got, 1000, "split size %d", splitSize)
+		for i, v := range got {
+			assert.Equal(t, int64(i), v)
+		}
+	}
+}
+
+func TestNewRangeDecoder_Concurrent(t *testing.T) {
+	data := encodeLongs(t, 1000, ocf.WithBlockLength(13))
+	size := int64(len(data))
+	mid := size / 2
+
+	first := readRange(t, data, 0, mid, ocf.WithDecoderConcurrency(4))
+	second := readRange(t, data, mid, size, ocf.WithDecoderConcurrency(4))
+
+	assert.NotEmpty(t, first)
+	assert.NotEmpty(t, second)
+	assert.Len(t, append(first, second...), 1000)
+	assert.Equal(t, first[len(first)-1]+1, second[0])
+}
+
+func TestNewRangeDecoder_EmptyRange(t *testing.T) {
+	data := encodeLongs(t, 100, ocf.WithBlockLength(7))
+	size := int64(len(data))
+
+	got := readRange(t, data, size-10, size)
+
+	assert.Empty(t, got)
+}
+
+func TestNewRangeDecoder_InvalidRange(t *testing.T) {
+	data := encodeLongs(t, 10)
+
+	_, err := ocf.NewRangeDecoder(bytes.NewReader(data), int64(len(data)), 10, 5)
+
+	assert.Error(t, err)
+}
+
+func TestNewRangeDecoder_InvalidHeader(t *testing.T) {
+	data := []byte("not an avro file")
+
+	_, err := ocf.NewRangeDecoder(bytes.NewReader(data), int64(len(data)), 0, int64(len(data)))
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,345 @@
+package ocf
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+
+	"github.com/hamba/avro/v2"
+)
+
+// maxRecoveryBlockSize is the largest block size accepted in recovery mode.
+// Larger sizes are assumed to be corrupted.
+const maxRecoveryBlockSize = 64 << 20
+
+// BlockError describes a corrupted block found in recovery mode.
+type BlockError struct {
+	// Offset is the byte offset of the block in the file.
+	Offset int64
+	// Err is the reason the block is corrupted.
+	Err error
+}
+
+// Error returns the error message.
+func (e *BlockError) Error() string {
+	return fmt.Sprintf("decoder: corrupted block at offset %d: %v", e.Offset, e.Err)
+}
+
+// Unwrap returns the reason the block is corrupted.
+func (e *BlockError) Unwrap() error {
+	return e.Err
+}
+
+// recovery reads blocks byte by byte, so that the bytes of a corrupted
+// block can be scanned again for the next sync marker.
+type recovery struct {
+	src *bufio.Reader
+	fn  func(*BlockError)
+
+	pending  []byte // Bytes to read again before src.
+	consumed []byte // Bytes read for the current block.
+	record   bool
+}
+
+func newRecoveringDecoder(r io.Reader, cfg decoderConfig) (*Decoder, error) {
+	d, src, err := newOffsetDecoder(r, cfg)
+	if err != nil {
+		return nil, err
+	}
+	d.recovery = &recovery{src: src, fn: cfg.Recovery}
+	return d, nil
+}
+
+// newOffsetDecoder returns a decoder that knows the offset of the blocks in
+// the file, along with the buffered reader the blocks are read from.
+func newOffsetDecoder(r io.Reader, cfg decoderConfig) (*Decoder, *bufio.Reader, error) {
+	src := bufio.NewReader(r)
+	cr := &countingReader{r: src}
+
+	// The header is read without buffering, leaving the blocks in src.
+	h, err := readHeader(avro.NewReader(cr, 1), cfg.SchemaCache, cfg.CodecOptions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoder: %w", err)
+	}
+
+	d, err := newDecoderFromHeader(h, avro.NewReader(src, 1024), cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	d.offset = cr.n
+	return d, src, nil
+}
+
+// readRecoveredBlock reads the next readable block, returning its record
+// count and its decompressed data. Corrupted blocks are reported and skipped.
+// It returns false if there is no next block.
+func (d *Decoder) readRecoveredBlock() (int64, []byte, bool) {
+	rec := d.recovery
+	for {
+		if d.pastRange() {
+			return 0, nil, false
+		}
+
+		start := d.offset
+		rec.consumed = rec.consumed[:0]
+		rec.record = true
+
+		count, data, err := d.readFramedBlock()
+		switch {
+		case errors.Is(err, io.EOF) && len(rec.consumed) == 0:
+			return 0, nil, false
+		case errors.Is(err, io.EOF):
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			if !errors.Is(err, io.ErrUnexpectedEOF) && !errors.As(err, new(corruptError)) {
+				d.reader.Error = err
+				return 0, nil, false
+			}
+			rec.fn(&BlockError{Offset: start, Err: err})
+			if !d.resync(start) {
+				return 0, nil, false
+			}
+			continue
+		}
+		if count == 0 {
+			continue
+		}
+
+		data, err = d.codec.Decode(data)
+		if err != nil {
+			rec.fn(&BlockError{Offset: start, Err: err})
+			continue
+		}
+
+		d.blockOffset = start
+		return count, data, true
+	}
+}
+
+// corruptError is returned for invalid block framing.
+type corruptError string
+
+func (e corruptError) Error() string {
+	return string(e)
+}
+
+func (d *Decoder) readFramedBlock() (int64, []byte, error) {
+	count, err := d.readRecoveryLong()
+	if err != nil {
+		return 0, nil, err
+	}
+	if count < 0 {
+		return 0, nil, corruptError("invalid block count")
+	}
+
+	size, err := d.readRecoveryLong()
+	if err != nil {
+		return 0, nil, err
+	}
+	if size < 0 || size > maxRecoveryBlockSize {
+		return 0, nil, corruptError("invalid block size")
+	}
+
+	data, err := d.readRecoveryBytes(int(size))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	sync, err := d.readRecoveryBytes(len(d.sync))
+	if err != nil {
+		return 0, nil, err
+	}
+	if !bytes.Equal(sync, d.sync[:]) {
+		return 0, nil, corruptError("invalid sync marker")
+	}
+
+	return count, data, nil
+}
+
+// resync positions the decoder after the first sync marker following the
+// start of the corrupted block at start. It returns false if there is none.
+func (d *Decoder) resync(start int64) bool {
+	rec := d.recovery
+
+	rec.pending = append(bytes.Clone(rec.consumed[1:]), rec.pending...)
+	rec.record = false
+	d.offset = start + 1
+
+	window := make([]byte, 0, len(d.sync))
+	for {
+		b, err := d.readRecoveryByte()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				d.reader.Error = err
+			}
+			return false
+		}
+
+		if len(window) == cap(window) {
+			copy(window, window[1:])
+			window = window[:len(window)-1]
+		}
+		window = append(window, b)
+		if bytes.Equal(window, d.sync[:]) {
+			return true
+		}
+	}
+}
+
+func (d *Decoder) readRecoveryByte() (byte, error) {
+	rec := d.recovery
+
+	var b byte
+	if len(rec.pending) > 0 {
+		b = rec.pending[0]
+		rec.pending = rec.pending[1:]
+	} else {
+		var err error
+		if b, err = rec.src.ReadByte(); err != nil {
+			return 0, err
+		}
+	}
+
+	d.offset++
+	if rec.record {
+		rec.consumed = append(rec.consumed, b)
+	}
+	return b, nil
+}
+
+func (d *Decoder) readRecoveryBytes(n int) ([]byte, error) {
+	rec := d.recovery
+
+	b := make([]byte, n)
+	read := copy(b, rec.pending)
+	rec.pending = rec.pending[read:]
+
+	m, err := io.ReadFull(rec.src, b[read:])
+	read += m
+
+	d.offset += int64(read)
+	if rec.record {
+		rec.consumed = append(rec.consumed, b[:read]...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// readRecoveryLong reads a zig-zag encoded long.
+func (d *Decoder) readRecoveryLong() (int64, error) {
+	var (
+		value uint64
+		shift uint
+	)
+	for range maxLongSize {
+		b, err := d.readRecoveryByte()
+		if err != nil {
+			return 0, err
+		}
+
+		value |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return int64(value>>1) ^ -int64(value&1), nil
+		}
+		shift += 7
+	}
+	return 0, corruptError("invalid long")
+}
+
+const maxLongSize = 10
+
+// recordError reports a value that failed to decode in recovery mode, skipping
+// the rest of its block.
+func (d *Decoder) recordError(err error) error {
+	if err == nil || d.recovery == nil {
+		return err
+	}
+
+	d.recovery.fn(&BlockError{Offset: d.blockOffset, Err: err})
+	d.count = 0
+	d.decoder = d.cfg.DecoderConfig.NewDecoder(d.readSchema, d.resetReader)
+	return err
+}
+
+// Salvage copies the readable blocks of the container file in r to a new
+// container file in w, returning the number of records copied.
+//
+// Corrupted blocks, and blocks holding values that fail to decode, are
+// reported to fn and left out. The new file keeps the schema, codec and
+// metadata of the original file.
+func Salvage(w io.Writer, r io.Reader, fn func(*BlockError), opts ...DecoderFunc) (int64, error) {
+	if fn == nil {
+		fn = func(*BlockError) {}
+	}
+
+	cfg := computeDecoderConfig(opts)
+	cfg.Recovery = fn
+
+	dec, err := newRecoveringDecoder(r, cfg)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = dec.Close() }()
+
+	meta := maps.Clone(dec.meta)
+	schemaJSON := meta[schemaKey]
+	bw, err := NewBlockWriter(dec.schema, w,
+		WithCodec(codecName(meta[codecKey])),
+		WithMetadata(meta),
+		WithSchemaMarshaler(func(avro.Schema) ([]byte, error) { return schemaJSON, nil }),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	reader := avro.NewReader(nil, 0, avro.WithReaderConfig(cfg.DecoderConfig))
+
+	var n int64
+	for {
+		count, data, ok := dec.readRecoveredBlock()
+		if !ok {
+			break
+		}
+
+		reader.Reset(data)
+		for range count {
+			_ = reader.ReadNext(dec.schema)
+			if reader.Error != nil {
+				break
+			}
+		}
+		if reader.Error != nil {
+			fn(&BlockError{Offset: dec.blockOffset, Err: reader.Error})
+			reader.Error = nil
+			continue
+		}
+
+		if err = bw.WriteRecords(count, data); err != nil {
+			return n, err
+		}
+		n += count
+	}
+	if err = dec.Error(); err != nil {
+		return n, err
+	}
+
+	return n, bw.Close()
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
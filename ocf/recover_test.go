@@ -0,0 +1,200 @@
+package ocf_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSync = [16]byte{0xde, 0xad, 0xbe, 0xef, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+
+// syncOffsets returns the offsets of the sync markers in data, the first
+// being the header sync marker.
+func syncOffsets(data []byte) []int {
+	var offsets []int
+	for i := 0; ; {
+		j := bytes.Index(data[i:], testSync[:])
+		if j < 0 {
+			return offsets
+		}
+		offsets = append(offsets, i+j)
+		i += j + len(testSync)
+	}
+}
+
+func decodeRecovering(t *testing.T, data []byte) ([]int64, []*ocf.BlockError) {
+	t.Helper()
+
+	var errs []*ocf.BlockError
+	dec, err := ocf.NewDecoder(bytes.NewReader(data), ocf.WithDecoderRecovery(func(err *ocf.BlockError) {
+		errs = append(errs, err)
+	}))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = dec.Close() })
+
+	var got []int64
+	for dec.HasNext() {
+		var v int64
+		if err = dec.Decode(&v); err != nil {
+			continue
+		}
+		got = append(got, v)
+	}
+	require.NoError(t, dec.Error())
+	return got, errs
+}
+
+func TestDecoder_RecoveryNoCorruption(t *testing.T) {
+	data := encodeLongs(t, 20, ocf.WithBlockLength(4), ocf.WithSyncBlock(testSync))
+
+	got, errs := decodeRecovering(t, data)
+
+	assert.Len(t, got, 20)
+	assert.Empty(t, errs)
+}
+
+func TestDecoder_RecoveryInvalidSyncMarker(t *testing.T) {
+	data := encodeLongs(t, 20, ocf.WithBlockLength(4), ocf.WithSyncBlock(testSync))
+	offsets := syncOffsets(data)
+	require.Len(t, offsets, 6)
+
+	// Corrupt the sync marker of the second block.
+	data[offsets[2]+3] ^= 0xff
+
+	got, errs := decodeRecovering(t, data)
+
+	// The second block is corrupted, and the third is lost looking for the next sync marker.
+	assert.Equal(t, []int64{0, 1, 2, 3, 12, 13, 14, 15, 16, 17, 18, 19}, got)
+	require.Len(t, errs, 1)
+	assert.Equal(t, int64(offsets[1]+16), errs[0].Offset)
+	assert.ErrorContains(t, errs[0], "invalid sync marker")
+}
+
+func TestDecoder_RecoveryInvalidBlockSize(t *testing.T) {
+	data := encodeLongs(t, 20, ocf.WithBlockLength(4), ocf.WithSyncBlock(testSync))
+	offsets := syncOffsets(data)
+
+	// Corrupt the size of the second block to a huge value.
+	data[offsets[1]+17] = 0xff
+	data = append(data[:offsets[1]+18:offsets[1]+18], append([]byte{0xff, 0xff, 0xff, 0x7f}, data[offsets[1]+18:]...)...)
+
+	got, errs := decodeRecovering(t, data)
+
+	assert.Equal(t, []int64{0, 1, 2, 3, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19}, got)
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "invalid block size")
+}
+
+func TestDecoder_RecoveryCodecError(t *testing.T) {
+	data := encodeLongs(t, 20, ocf.WithCodec(ocf.Snappy), ocf.WithBlockLength(4), ocf.WithSyncBlock(testSync))
+	offsets := syncOffsets(data)
+
+	// Corrupt the checksum of the third block.
+	data[offsets[3]-1] ^= 0xff
+
+	got, errs := decodeRecovering(t, data)
+
+	assert.Equal(t, []int64{0, 1, 2, 3, 4, 5, 6, 7, 12, 13, 14, 15, 16, 17, 18, 19}, got)
+	require.Len(t, errs, 1)
+	assert.Equal(t, int64(offsets[2]+16), errs[0].Offset)
+}
+
+func TestDecoder_RecoveryTruncated(t *testing.T) {
+	data := encodeLongs(t, 20, ocf.WithBlockLength(4), ocf.WithSyncBlock(testSync))
+
+	got, errs := decodeRecovering(t, data[:len(data)-5])
+
+	assert.Equal(t, []int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, got)
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "unexpected EOF")
+}
+
+func TestDecoder_RecoveryValueError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"string"`, buf, ocf.WithBlockLength(2), ocf.WithSyncBlock(testSync))
+	require.NoError(t, err)
+	for _, s := range []string{"a", "b", "c", "d", "e", "f"} {
+		require.NoError(t, enc.Encode(s))
+	}
+	require.NoError(t, enc.Close())
+	data := buf.Bytes()
+	offsets := syncOffsets(data)
+
+	// Corrupt the length of the first string of the second block.
+	data[offsets[2]-4] = 0x7f
+
+	var errs []*ocf.BlockError
+	dec, err := ocf.NewDecoder(bytes.NewReader(data), ocf.WithDecoderRecovery(func(err *ocf.BlockError) {
+		errs = append(errs, err)
+	}))
+	require.NoError(t, err)
+
+	var got []string
+	var decodeErrs int
+	for dec.HasNext() {
+		var s string
+		if err = dec.Decode(&s); err != nil {
+			decodeErrs++
+			continue
+		}
+		got = append(got, s)
+	}
+
+	require.NoError(t, dec.Error())
+	assert.Equal(t, []string{"a", "b", "e", "f"}, got)
+	assert.Equal(t, 1, decodeErrs)
+	assert.Len(t, errs, 1)
+}
+
+func TestSalvage(t *testing.T) {
+	data := encodeLongs(t, 20, ocf.WithCodec(ocf.Deflate), ocf.WithBlockLength(4), ocf.WithSyncBlock(testSync),
+		ocf.WithMetadataKeyVal("foo", []byte("bar")))
+	offsets := syncOffsets(data)
+	data[offsets[2]+3] ^= 0xff
+	data = data[:len(data)-5]
+
+	var errs []*ocf.BlockError
+	buf := &bytes.Buffer{}
+	n, err := ocf.Salvage(buf, bytes.NewReader(data), func(err *ocf.BlockError) {
+		errs = append(errs, err)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), n)
+	assert.Len(t, errs, 2)
+
+	dec, err := ocf.NewDecoder(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("bar"), dec.Metadata()["foo"])
+	assert.Equal(t, []byte("deflate"), dec.Metadata()["avro.codec"])
+	assert.Equal(t, []int64{0, 1, 2, 3, 12, 13, 14, 15}, decodeLongs(t, buf.Bytes()))
+}
+
+func TestSalvage_InvalidHeader(t *testing.T) {
+	_, err := ocf.Salvage(&bytes.Buffer{}, bytes.NewReader([]byte("not an avro file")), nil)
+
+	assert.Error(t, err)
+}
+
+func TestSalvage_LimitsPerValue(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"string"`, buf, ocf.WithBlockLength(100))
+	require.NoError(t, err)
+	for range 100 {
+		require.NoError(t, enc.Encode(strings.Repeat("a", 100)))
+	}
+	require.NoError(t, enc.Close())
+
+	cfg := avro.Config{MaxTotalAllocation: 1000}.Freeze()
+	n, err := ocf.Salvage(&bytes.Buffer{}, bytes.NewReader(buf.Bytes()), func(err *ocf.BlockError) {
+		t.Errorf("unexpected block error: %v", err)
+	}, ocf.WithDecoderConfig(cfg))
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), n)
+}
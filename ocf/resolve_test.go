@@ -0,0 +1,116 @@
+package ocf_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const writerSchema = `{
+	"type": "record",
+	"name": "test",
+	"fields": [
+		{"name": "a", "type": "int"},
+		{"name": "b", "type": "string"},
+		{"name": "c", "type": {"type": "array", "items": "string"}}
+	]
+}`
+
+const readerSchema = `{
+	"type": "record",
+	"name": "test",
+	"fields": [
+		{"name": "a", "type": "long"},
+		{"name": "d", "type": "string", "default": "dflt"}
+	]
+}`
+
+type readerRecord struct {
+	A int64  `avro:"a"`
+	D string `avro:"d"`
+}
+
+func encodeWriterRecords(t *testing.T) []byte {
+	t.Helper()
+
+	type record struct {
+		A int      `avro:"a"`
+		B string   `avro:"b"`
+		C []string `avro:"c"`
+	}
+
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(writerSchema, buf, ocf.WithBlockLength(2))
+	require.NoError(t, err)
+	for i := range 3 {
+		require.NoError(t, enc.Encode(record{A: i, B: "foo", C: []string{"x", "y"}}))
+	}
+	require.NoError(t, enc.Close())
+	return buf.Bytes()
+}
+
+func TestDecoder_WithReaderSchema(t *testing.T) {
+	data := encodeWriterRecords(t)
+
+	dec, err := ocf.NewDecoder(bytes.NewReader(data), ocf.WithReaderSchema(avro.MustParse(readerSchema)))
+	require.NoError(t, err)
+
+	var got []readerRecord
+	for dec.HasNext() {
+		var rec readerRecord
+		require.NoError(t, dec.Decode(&rec))
+		got = append(got, rec)
+	}
+
+	require.NoError(t, dec.Error())
+	assert.Equal(t, []readerRecord{{A: 0, D: "dflt"}, {A: 1, D: "dflt"}, {A: 2, D: "dflt"}}, got)
+	assert.Equal(t, avro.MustParse(writerSchema).Fingerprint(), dec.Schema().Fingerprint())
+}
+
+func TestDecoder_WithReaderSchemaGeneric(t *testing.T) {
+	data := encodeWriterRecords(t)
+
+	dec, err := ocf.NewDecoder(bytes.NewReader(data), ocf.WithReaderSchema(avro.MustParse(readerSchema)))
+	require.NoError(t, err)
+
+	var got []any
+	for v, err := range ocf.AllGeneric(dec) {
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+
+	require.Len(t, got, 3)
+	assert.Equal(t, map[string]any{"a": int64(2), "d": "dflt"}, got[2])
+}
+
+func TestDecoder_WithReaderSchemaIncompatible(t *testing.T) {
+	data := encodeWriterRecords(t)
+	schema := avro.MustParse(`{"type": "record", "name": "test", "fields": [{"name": "e", "type": "string"}]}`)
+
+	_, err := ocf.NewDecoder(bytes.NewReader(data), ocf.WithReaderSchema(schema))
+
+	assert.Error(t, err)
+}
+
+func TestTypedDecoder_WithReaderSchema(t *testing.T) {
+	data := encodeWriterRecords(t)
+
+	dec, err := ocf.NewTypedDecoder[readerRecord](bytes.NewReader(data),
+		ocf.WithReaderSchema(avro.MustParse(readerSchema)),
+		ocf.WithDecoderConcurrency(2),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = dec.Close() })
+
+	var got []readerRecord
+	for rec, err := range dec.All() {
+		require.NoError(t, err)
+		got = append(got, rec)
+	}
+
+	assert.Equal(t, []readerRecord{{A: 0, D: "dflt"}, {A: 1, D: "dflt"}, {A: 2, D: "dflt"}}, got)
+}
@@ -0,0 +1,295 @@
+package ocf
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hamba/avro/v2"
+)
+
+// FileStats describes a file written by a RollingWriter.
+type FileStats struct {
+	// Name is the name of the file.
+	Name string
+	// Records is the number of records written to the file.
+	Records int64
+	// Size is the size of the file in bytes.
+	Size int64
+	// Opened is the time the file was opened.
+	Opened time.Time
+	// Closed is the time the file was closed.
+	Closed time.Time
+}
+
+type rollingConfig struct {
+	MaxSize     int64
+	MaxRecords  int64
+	MaxAge      time.Duration
+	OnRotate    func(FileStats)
+	Create      func(name string) (io.WriteCloser, error)
+	EncoderOpts []EncoderFunc
+}
+
+// RollingWriterFunc represents a configuration function for RollingWriter.
+type RollingWriterFunc func(cfg *rollingConfig)
+
+// WithMaxFileSize rotates files once their size reaches size bytes.
+// The size includes the block being built, so files exceed it by at most
+// one record.
+func WithMaxFileSize(size int64) RollingWriterFunc {
+	return func(cfg *rollingConfig) {
+		cfg.MaxSize = size
+	}
+}
+
+// WithMaxFileRecords rotates files once n records have been written to them.
+func WithMaxFileRecords(n int64) RollingWriterFunc {
+	return func(cfg *rollingConfig) {
+		cfg.MaxRecords = n
+	}
+}
+
+// WithMaxFileAge rotates files once they have been open for d, even if no
+// records are written in the meantime.
+func WithMaxFileAge(d time.Duration) RollingWriterFunc {
+	return func(cfg *rollingConfig) {
+		cfg.MaxAge = d
+	}
+}
+
+// WithRotateFunc sets a function called with the stats of every file once it
+// is closed. It is called while the writer is locked, so it must not use the
+// writer.
+func WithRotateFunc(fn func(FileStats)) RollingWriterFunc {
+	return func(cfg *rollingConfig) {
+		cfg.OnRotate = fn
+	}
+}
+
+// WithCreateFunc sets the function used to create files. It defaults to os.Create.
+func WithCreateFunc(fn func(name string) (io.WriteCloser, error)) RollingWriterFunc {
+	return func(cfg *rollingConfig) {
+		cfg.Create = fn
+	}
+}
+
+// WithEncoderOptions sets the options of the encoder of every file.
+func WithEncoderOptions(opts ...EncoderFunc) RollingWriterFunc {
+	return func(cfg *rollingConfig) {
+		cfg.EncoderOpts = opts
+	}
+}
+
+// RollingWriter writes Avro values to a sequence of container files,
+// rotating to a new file when a size, record count or age threshold is
+// reached. Every file is closed once rotated, so it is a valid container file.
+//
+// Files are created lazily, when the first value is written to them.
+// A RollingWriter is safe for concurrent use.
+type RollingWriter struct {
+	schema avro.Schema
+	name   func(seq int) string
+	cfg    rollingConfig
+
+	mu      sync.Mutex
+	seq     int
+	file    io.WriteCloser
+	written *countingWriter
+	enc     *Encoder
+	stats   FileStats
+	timer   *time.Timer
+	err     error
+	closed  bool
+}
+
+// NewRollingWriter returns a new rolling writer that writes values with
+// schema to files named by name, where seq is the sequence number of the
+// file, starting at 0.
+func NewRollingWriter(schema avro.Schema, name func(seq int) string, opts ...RollingWriterFunc) (*RollingWriter, error) {
+	if name == nil {
+		return nil, errors.New("rolling writer: name function cannot be nil")
+	}
+
+	cfg := rollingConfig{
+		Create: func(name string) (io.WriteCloser, error) {
+			return os.Create(name) //nolint:gosec // The name is provided by the user.
+		},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &RollingWriter{
+		schema: schema,
+		name:   name,
+		cfg:    cfg,
+	}, nil
+}
+
+// Encode writes the Avro encoding of v to the current file, rotating the
+// file if a threshold is reached.
+func (w *RollingWriter) Encode(v any) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return errors.New("rolling writer: closed")
+	}
+	if err := w.err; err != nil {
+		w.err = nil
+		return err
+	}
+
+	if w.enc == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+
+	if err := w.enc.Encode(v); err != nil {
+		return err
+	}
+	w.stats.Records++
+
+	if w.shouldRotate() {
+		return w.rotate()
+	}
+	return nil
+}
+
+// Flush writes the buffered records of the current file.
+func (w *RollingWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.enc == nil {
+		return nil
+	}
+	return w.enc.Flush()
+}
+
+// Rotate closes the current file, if any. The next value is written to a
+// new file.
+func (w *RollingWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.rotate()
+}
+
+// Close closes the current file, if any, and the writer.
+func (w *RollingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	err := w.rotate()
+	if w.err != nil {
+		err = errors.Join(w.err, err)
+		w.err = nil
+	}
+	return err
+}
+
+func (w *RollingWriter) open() error {
+	name := w.name(w.seq)
+	w.seq++
+
+	file, err := w.cfg.Create(name)
+	if err != nil {
+		return err
+	}
+
+	written := &countingWriter{w: file}
+	enc, err := NewEncoderWithSchema(w.schema, written, w.cfg.EncoderOpts...)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	w.file = file
+	w.written = written
+	w.enc = enc
+	w.stats = FileStats{Name: name, Opened: time.Now()}
+
+	if w.cfg.MaxAge > 0 {
+		seq := w.seq
+		w.timer = time.AfterFunc(w.cfg.MaxAge, func() { w.expire(seq) })
+	}
+	return nil
+}
+
+func (w *RollingWriter) shouldRotate() bool {
+	if w.cfg.MaxRecords > 0 && w.stats.Records >= w.cfg.MaxRecords {
+		return true
+	}
+	if w.cfg.MaxSize > 0 && w.written.n.Load()+int64(w.enc.buf.Len()) >= w.cfg.MaxSize {
+		return true
+	}
+	return false
+}
+
+// expire rotates the file with sequence number seq, if it is still open.
+func (w *RollingWriter) expire(seq int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.enc == nil || w.seq != seq {
+		return
+	}
+	if err := w.rotate(); err != nil && w.err == nil {
+		w.err = err
+	}
+}
+
+func (w *RollingWriter) rotate() error {
+	if w.enc == nil {
+		return nil
+	}
+
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+
+	err := w.enc.Close()
+	if ferr := w.file.Close(); ferr != nil && err == nil {
+		err = ferr
+	}
+
+	stats := w.stats
+	stats.Size = w.written.n.Load()
+	stats.Closed = time.Now()
+
+	w.enc = nil
+	w.file = nil
+	w.written = nil
+
+	if err != nil {
+		return err
+	}
+	if w.cfg.OnRotate != nil {
+		w.cfg.OnRotate(stats)
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n atomic.Int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n.Add(int64(n))
+	return n, err
+}
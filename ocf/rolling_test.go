@@ -0,0 +1,181 @@
+package ocf_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memFiles struct {
+	mu    sync.Mutex
+	files map[string]*memFile
+}
+
+type memFile struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (f *memFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (m *memFiles) create(name string) (io.WriteCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.files == nil {
+		m.files = map[string]*memFile{}
+	}
+	f := &memFile{}
+	m.files[name] = f
+	return f, nil
+}
+
+func (m *memFiles) get(name string) *memFile {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.files[name]
+}
+
+func fileName(seq int) string {
+	return fmt.Sprintf("file-%d.avro", seq)
+}
+
+func TestRollingWriter_MaxRecords(t *testing.T) {
+	files := &memFiles{}
+	var stats []ocf.FileStats
+	w, err := ocf.NewRollingWriter(avro.MustParse(`"long"`), fileName,
+		ocf.WithMaxFileRecords(4),
+		ocf.WithCreateFunc(files.create),
+		ocf.WithRotateFunc(func(s ocf.FileStats) { stats = append(stats, s) }),
+	)
+	require.NoError(t, err)
+
+	for i := range 10 {
+		require.NoError(t, w.Encode(int64(i)))
+	}
+	require.NoError(t, w.Close())
+
+	require.Len(t, stats, 3)
+	assert.Equal(t, []int64{0, 1, 2, 3}, decodeLongs(t, files.get("file-0.avro").Bytes()))
+	assert.Equal(t, []int64{4, 5, 6, 7}, decodeLongs(t, files.get("file-1.avro").Bytes()))
+	assert.Equal(t, []int64{8, 9}, decodeLongs(t, files.get("file-2.avro").Bytes()))
+	for i, s := range stats {
+		f := files.get(s.Name)
+		assert.True(t, f.closed)
+		assert.Equal(t, fileName(i), s.Name)
+		assert.Equal(t, int64(f.Len()), s.Size)
+		assert.False(t, s.Closed.Before(s.Opened))
+	}
+	assert.Equal(t, int64(2), stats[2].Records)
+}
+
+func TestRollingWriter_MaxSize(t *testing.T) {
+	files := &memFiles{}
+	var stats []ocf.FileStats
+	w, err := ocf.NewRollingWriter(avro.MustParse(`"string"`), fileName,
+		ocf.WithMaxFileSize(1024),
+		ocf.WithCreateFunc(files.create),
+		ocf.WithRotateFunc(func(s ocf.FileStats) { stats = append(stats, s) }),
+		ocf.WithEncoderOptions(ocf.WithBlockLength(10)),
+	)
+	require.NoError(t, err)
+
+	value := string(bytes.Repeat([]byte("a"), 100))
+	for range 50 {
+		require.NoError(t, w.Encode(value))
+	}
+	require.NoError(t, w.Close())
+
+	require.Greater(t, len(stats), 3)
+	var records int64
+	for _, s := range stats {
+		assert.Less(t, s.Size, int64(1024+2*len(value)))
+		records += s.Records
+	}
+	assert.Equal(t, int64(50), records)
+}
+
+func TestRollingWriter_MaxAge(t *testing.T) {
+	files := &memFiles{}
+	rotated := make(chan ocf.FileStats, 2)
+	w, err := ocf.NewRollingWriter(avro.MustParse(`"long"`), fileName,
+		ocf.WithMaxFileAge(20*time.Millisecond),
+		ocf.WithCreateFunc(files.create),
+		ocf.WithRotateFunc(func(s ocf.FileStats) { rotated <- s }),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Encode(int64(1)))
+
+	select {
+	case s := <-rotated:
+		assert.Equal(t, "file-0.avro", s.Name)
+		assert.Equal(t, int64(1), s.Records)
+	case <-time.After(time.Second):
+		require.FailNow(t, "file was not rotated")
+	}
+
+	require.NoError(t, w.Encode(int64(2)))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []int64{1}, decodeLongs(t, files.get("file-0.avro").Bytes()))
+	assert.Equal(t, []int64{2}, decodeLongs(t, files.get("file-1.avro").Bytes()))
+}
+
+func TestRollingWriter_NoEmptyFiles(t *testing.T) {
+	files := &memFiles{}
+	w, err := ocf.NewRollingWriter(avro.MustParse(`"long"`), fileName,
+		ocf.WithMaxFileRecords(2),
+		ocf.WithCreateFunc(files.create),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Encode(int64(1)))
+	require.NoError(t, w.Encode(int64(2)))
+	require.NoError(t, w.Rotate())
+	require.NoError(t, w.Close())
+
+	assert.Len(t, files.files, 1)
+}
+
+func TestRollingWriter_EncodeAfterClose(t *testing.T) {
+	w, err := ocf.NewRollingWriter(avro.MustParse(`"long"`), fileName, ocf.WithCreateFunc((&memFiles{}).create))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	err = w.Encode(int64(1))
+
+	assert.Error(t, err)
+}
+
+func TestRollingWriter_CreatesFiles(t *testing.T) {
+	dir := t.TempDir()
+	w, err := ocf.NewRollingWriter(avro.MustParse(`"long"`), func(seq int) string {
+		return filepath.Join(dir, fmt.Sprintf("%03d.avro", seq))
+	}, ocf.WithMaxFileRecords(1))
+	require.NoError(t, err)
+
+	require.NoError(t, w.Encode(int64(1)))
+	require.NoError(t, w.Encode(int64(2)))
+	require.NoError(t, w.Close())
+
+	for i, name := range []string{"000.avro", "001.avro"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, []int64{int64(i + 1)}, decodeLongs(t, data))
+	}
+}
@@ -0,0 +1,143 @@
+package ocf
+
+import (
+	"errors"
+	"io"
+	"iter"
+
+	"github.com/hamba/avro/v2"
+)
+
+// TypedDecoder reads and decodes Avro values of type T from a container file.
+type TypedDecoder[T any] struct {
+	*Decoder
+
+	codec   *avro.TypedCodec[T]
+	decoder *avro.TypedDecoder[T]
+}
+
+// NewTypedDecoder returns a new decoder that reads values of type T from reader r.
+//
+// The type is validated against the file schema when the decoder is created.
+func NewTypedDecoder[T any](r io.Reader, opts ...DecoderFunc) (*TypedDecoder[T], error) {
+	cfg := computeDecoderConfig(opts)
+
+	dec, err := newDecoder(r, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, err := avro.NewTypedDecodingCodecWithAPI[T](dec.readSchema, cfg.DecoderConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Concurrency > 1 {
+		dec.decodeBlock = func(r *avro.Reader, count int64) any {
+			values := make([]T, count)
+			for i := range values {
+				codec.Read(r, &values[i])
+				if r.Error != nil {
+					break
+				}
+			}
+			return values
+		}
+	}
+
+	return &TypedDecoder[T]{
+		Decoder: dec,
+		codec:   codec,
+		decoder: codec.NewDecoder(dec.resetReader),
+	}, nil
+}
+
+// Decode reads the next Avro encoded value from its input and returns it.
+func (d *TypedDecoder[T]) Decode() (T, error) {
+	var v T
+	err := d.DecodeInto(&v)
+	return v, err
+}
+
+// DecodeInto reads the next Avro encoded value from its input and stores it in the value pointed to by v.
+func (d *TypedDecoder[T]) DecodeInto(v *T) error {
+	if d.count <= 0 {
+		return errors.New("decoder: no data found, call HasNext first")
+	}
+
+	// Values may have been decoded ahead by a concurrent decoder.
+	if values, ok := d.values.([]T); ok {
+		*v = values[d.blockCount-d.count]
+		d.count--
+		return nil
+	}
+
+	d.count--
+
+	err := d.recordError(d.decoder.DecodeInto(v))
+	if err != nil && d.recovery != nil {
+		d.decoder = d.codec.NewDecoder(d.resetReader)
+	}
+	return err
+}
+
+// All returns an iterator over the values decoded from the file.
+// Iteration stops at the end of the file, or after the first error is yielded.
+//
+// Breaking out of the loop leaves the decoder positioned after the last
+// yielded value. The caller remains responsible for closing the decoder.
+func (d *TypedDecoder[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for d.HasNext() {
+			v, err := d.Decode()
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+		if err := d.Error(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// TypedEncoder writes Avro values of type T to a container file.
+type TypedEncoder[T any] struct {
+	*Encoder
+
+	encoder *avro.TypedEncoder[T]
+}
+
+// NewTypedEncoder returns a new encoder that writes values of type T to w using schema.
+//
+// If the writer is an existing ocf file, it will append data using the
+// existing schema. The type is validated against the schema when the encoder
+// is created.
+func NewTypedEncoder[T any](schema avro.Schema, w io.Writer, opts ...EncoderFunc) (*TypedEncoder[T], error) {
+	cfg := computeEncoderConfig(opts)
+
+	enc, err := newEncoder(schema, w, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The schema of an existing file takes precedence over the given schema.
+	codec, err := avro.NewTypedCodecWithAPI[T](enc.schema, cfg.EncodingConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedEncoder[T]{
+		Encoder: enc,
+		encoder: codec.NewEncoder(enc.buf),
+	}, nil
+}
+
+// Encode writes the Avro encoding of v to the stream.
+func (e *TypedEncoder[T]) Encode(v T) error {
+	if err := e.encoder.Encode(v); err != nil {
+		return err
+	}
+
+	return e.encoded()
+}
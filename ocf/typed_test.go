@@ -0,0 +1,131 @@
+package ocf_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedDecoder(t *testing.T) {
+	f, err := os.Open("testdata/full.avro")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	dec, err := ocf.NewTypedDecoder[FullRecord](f)
+	require.NoError(t, err)
+
+	var count int
+	for dec.HasNext() {
+		count++
+		got, err := dec.Decode()
+
+		require.NoError(t, err)
+		assert.Equal(t, "I am a test record", got.Record.String)
+		assert.Equal(t, []int64{1, 2, 3, 4, 5}, got.Longs)
+	}
+
+	require.NoError(t, dec.Error())
+	assert.Equal(t, 1, count)
+}
+
+func TestTypedDecoder_TypeMismatch(t *testing.T) {
+	type mismatch struct {
+		Strings []int `avro:"strings"`
+	}
+
+	f, err := os.Open("testdata/full.avro")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	_, err = ocf.NewTypedDecoder[mismatch](f)
+
+	assert.Error(t, err)
+}
+
+func TestTypedDecoder_DecodeMustCallHasNext(t *testing.T) {
+	type record struct {
+		A int64 `avro:"a"`
+	}
+	schema := avro.MustParse(`{"type":"record","name":"test","fields":[{"name":"a","type":"long"}]}`)
+
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewTypedEncoder[record](schema, buf)
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(record{A: 1}))
+	require.NoError(t, enc.Close())
+
+	dec, err := ocf.NewTypedDecoder[record](buf)
+	require.NoError(t, err)
+
+	_, err = dec.Decode()
+
+	assert.Error(t, err)
+}
+
+func TestTypedEncoder_Roundtrip(t *testing.T) {
+	type record struct {
+		A int64  `avro:"a"`
+		B string `avro:"b"`
+	}
+	schema := avro.MustParse(`{"type":"record","name":"test","fields":[{"name":"a","type":"long"},{"name":"b","type":"string"}]}`)
+
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewTypedEncoder[record](schema, buf, ocf.WithBlockLength(2))
+	require.NoError(t, err)
+
+	want := []record{{A: 1, B: "foo"}, {A: 2, B: "bar"}, {A: 3, B: "baz"}}
+	for _, rec := range want {
+		require.NoError(t, enc.Encode(rec))
+	}
+	require.NoError(t, enc.Close())
+
+	dec, err := ocf.NewTypedDecoder[record](buf)
+	require.NoError(t, err)
+
+	var got []record
+	for dec.HasNext() {
+		rec, err := dec.Decode()
+		require.NoError(t, err)
+		got = append(got, rec)
+	}
+
+	require.NoError(t, dec.Error())
+	assert.Equal(t, want, got)
+}
+
+func TestNewTypedEncoder_TypeMismatch(t *testing.T) {
+	type record struct {
+		A string `avro:"a"`
+	}
+	schema := avro.MustParse(`{"type":"record","name":"test","fields":[{"name":"a","type":"long"}]}`)
+
+	_, err := ocf.NewTypedEncoder[record](schema, &bytes.Buffer{})
+
+	assert.Error(t, err)
+}
+
+func TestTypedDecoder_All(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewTypedEncoder[int64](avro.MustParse(`"long"`), buf, ocf.WithBlockLength(2))
+	require.NoError(t, err)
+	for i := range 5 {
+		require.NoError(t, enc.Encode(int64(i)))
+	}
+	require.NoError(t, enc.Close())
+
+	dec, err := ocf.NewTypedDecoder[int64](buf)
+	require.NoError(t, err)
+
+	var got []int64
+	for v, err := range dec.All() {
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+
+	assert.Equal(t, []int64{0, 1, 2, 3, 4}, got)
+}
@@ -0,0 +1,316 @@
+package avro
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// RandomOption is a function that sets a random generator option.
+type RandomOption func(*randomConfig)
+
+type randomConfig struct {
+	maxDepth  int
+	maxLength int
+}
+
+// WithRandomMaxDepth sets the maximum depth of nested records. Once reached,
+// unions pick null or a branch that is not a record where possible, and arrays
+// and maps are empty, so that recursive schemas terminate. Defaults to 5.
+func WithRandomMaxDepth(depth int) RandomOption {
+	return func(cfg *randomConfig) {
+		cfg.maxDepth = depth
+	}
+}
+
+// WithRandomMaxLength sets the maximum length of strings, bytes, arrays and
+// maps. Defaults to 10.
+func WithRandomMaxLength(length int) RandomOption {
+	return func(cfg *randomConfig) {
+		cfg.maxLength = length
+	}
+}
+
+// RandomGenerator generates random values conforming to a schema.
+//
+// Values are generic, of the same types as values decoded into an empty
+// interface, and can be marshaled with the schema.
+type RandomGenerator struct {
+	schema Schema
+	rnd    *rand.Rand
+	cfg    randomConfig
+
+	// records are the names of the records being generated.
+	records []string
+}
+
+// NewRandomGenerator returns a random generator of values of schema. The
+// generated values are deterministic for a seed.
+func NewRandomGenerator(schema Schema, seed int64, opts ...RandomOption) *RandomGenerator {
+	cfg := randomConfig{
+		maxDepth:  5,
+		maxLength: 10,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &RandomGenerator{
+		schema: schema,
+		rnd:    rand.New(rand.NewPCG(uint64(seed), 0)), //nolint:gosec // Random values are not used for security.
+		cfg:    cfg,
+	}
+}
+
+// Random returns a random value conforming to the schema.
+func Random(schema Schema, seed int64, opts ...RandomOption) (any, error) {
+	return NewRandomGenerator(schema, seed, opts...).Next()
+}
+
+// Next returns the next random value.
+func (g *RandomGenerator) Next() (any, error) {
+	return g.random(g.schema, 0)
+}
+
+func (g *RandomGenerator) random(schema Schema, depth int) (any, error) {
+	if schema.Type() == Ref {
+		schema = schema.(*RefSchema).Schema()
+	}
+
+	switch schema.Type() {
+	case Null:
+		return nil, nil
+
+	case Boolean:
+		return g.rnd.IntN(2) == 1, nil
+
+	case Int, Long, Float, Double, String, Bytes:
+		return g.primitive(schema.(*PrimitiveSchema)), nil
+
+	case Fixed:
+		return g.fixed(schema.(*FixedSchema)), nil
+
+	case Enum:
+		symbols := schema.(*EnumSchema).Symbols()
+		return symbols[g.rnd.IntN(len(symbols))], nil
+
+	case Array:
+		items := schema.(*ArraySchema).Items()
+		arr := make([]any, g.length(depth))
+		for i := range arr {
+			v, err := g.random(items, depth)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = v
+		}
+		return arr, nil
+
+	case Map:
+		values := schema.(*MapSchema).Values()
+		n := g.length(depth)
+		m := make(map[string]any, n)
+		for len(m) < n {
+			v, err := g.random(values, depth)
+			if err != nil {
+				return nil, err
+			}
+			m[g.string(1+g.rnd.IntN(max(g.cfg.maxLength, 1)))] = v
+		}
+		return m, nil
+
+	case Record:
+		name := schemaTypeName(schema)
+		if depth > g.cfg.maxDepth && slices.Contains(g.records, name) {
+			return nil, fmt.Errorf("avro: random: recursive record %s cannot end within max depth %d", name, g.cfg.maxDepth)
+		}
+		g.records = append(g.records, name)
+		defer func() { g.records = g.records[:len(g.records)-1] }()
+
+		fields := schema.(*RecordSchema).Fields()
+		m := make(map[string]any, len(fields))
+		for _, field := range fields {
+			v, err := g.random(field.Type(), depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field.Name(), err)
+			}
+			m[field.Name()] = v
+		}
+		return m, nil
+
+	case Union:
+		typ := g.unionType(schema.(*UnionSchema), depth)
+		if typ.Type() == Null {
+			return nil, nil
+		}
+		v, err := g.random(typ, depth)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{schemaTypeName(typ): v}, nil
+	}
+
+	return nil, errors.New("avro: random: unsupported schema type " + string(schema.Type()))
+}
+
+// unionType picks a random type of a union. Once the max depth is reached,
+// null or a type that is not a record is picked where possible.
+func (g *RandomGenerator) unionType(schema *UnionSchema, depth int) Schema {
+	types := schema.Types()
+	if depth < g.cfg.maxDepth {
+		return types[g.rnd.IntN(len(types))]
+	}
+
+	if _, pos := types.Get(string(Null)); pos >= 0 {
+		return types[pos]
+	}
+	var leaves Schemas
+	for _, typ := range types {
+		if typ.Type() == Ref {
+			typ = typ.(*RefSchema).Schema()
+		}
+		if typ.Type() != Record {
+			leaves = append(leaves, typ)
+		}
+	}
+	if len(leaves) == 0 {
+		leaves = types
+	}
+	return leaves[g.rnd.IntN(len(leaves))]
+}
+
+func (g *RandomGenerator) primitive(schema *PrimitiveSchema) any {
+	var logical LogicalType
+	if ls := schema.Logical(); ls != nil {
+		logical = ls.Type()
+	}
+
+	switch logical {
+	case Date:
+		// Days between 1970 and 2100.
+		days := g.rnd.Int64N(47482)
+		return time.Unix(days*86400, 0).UTC()
+	case TimeMillis:
+		return time.Duration(g.rnd.Int64N(int64(24*time.Hour/time.Millisecond))) * time.Millisecond
+	case TimeMicros:
+		return time.Duration(g.rnd.Int64N(int64(24*time.Hour/time.Microsecond))) * time.Microsecond
+	case TimestampMillis:
+		return time.UnixMilli(g.rnd.Int64N(4102444800000)).UTC()
+	case TimestampMicros:
+		return time.UnixMicro(g.rnd.Int64N(4102444800000000)).UTC()
+	case LocalTimestampMillis:
+		return localTime(time.UnixMilli(g.rnd.Int64N(4102444800000)))
+	case LocalTimestampMicros:
+		return localTime(time.UnixMicro(g.rnd.Int64N(4102444800000000)))
+	case UUID:
+		return g.uuid()
+	case Decimal:
+		return g.decimal(schema.Logical().(*DecimalLogicalSchema))
+	}
+
+	switch schema.Type() {
+	case Int:
+		return int(int32(g.rnd.Uint32())) //nolint:gosec // Overflow is intended.
+	case Long:
+		return int64(g.rnd.Uint64()) //nolint:gosec // Overflow is intended.
+	case Float:
+		return float32(g.float())
+	case Double:
+		return g.float()
+	case String:
+		return g.string(g.rnd.IntN(g.cfg.maxLength + 1))
+	default:
+		return g.bytes(g.rnd.IntN(g.cfg.maxLength + 1))
+	}
+}
+
+func (g *RandomGenerator) fixed(schema *FixedSchema) any {
+	if ls := schema.Logical(); ls != nil {
+		switch ls.Type() {
+		case Duration:
+			return LogicalDuration{
+				Months:       g.rnd.Uint32(),
+				Days:         g.rnd.Uint32(),
+				Milliseconds: g.rnd.Uint32(),
+			}
+		case Decimal:
+			return g.decimal(ls.(*DecimalLogicalSchema))
+		}
+	}
+	return byteSliceToArray(g.bytes(schema.Size()), schema.Size())
+}
+
+// localTime returns the local time with the wall clock of t in UTC.
+func localTime(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+}
+
+// decimal returns a random decimal of at most the precision digits.
+func (g *RandomGenerator) decimal(schema *DecimalLogicalSchema) *big.Rat {
+	digits := 1 + g.rnd.IntN(schema.Precision())
+	unscaled := new(big.Int)
+	ten := big.NewInt(10)
+	for range digits {
+		unscaled.Mul(unscaled, ten)
+		unscaled.Add(unscaled, big.NewInt(g.rnd.Int64N(10)))
+	}
+	if g.rnd.IntN(2) == 1 {
+		unscaled.Neg(unscaled)
+	}
+
+	scale := new(big.Int).Exp(ten, big.NewInt(int64(schema.Scale())), nil)
+	return new(big.Rat).SetFrac(unscaled, scale)
+}
+
+// length returns a random length of an array or map, which is zero once the
+// max depth is reached.
+func (g *RandomGenerator) length(depth int) int {
+	if depth >= g.cfg.maxDepth {
+		return 0
+	}
+	return g.rnd.IntN(g.cfg.maxLength + 1)
+}
+
+// float returns a random float, occasionally a special value.
+func (g *RandomGenerator) float() float64 {
+	switch g.rnd.IntN(100) {
+	case 0:
+		return 0
+	case 1:
+		return math.MaxFloat32
+	case 2:
+		return -math.MaxFloat32
+	}
+	return (g.rnd.Float64()*2 - 1) * 1e6
+}
+
+const randomChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func (g *RandomGenerator) string(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = randomChars[g.rnd.IntN(len(randomChars))]
+	}
+	return string(b)
+}
+
+func (g *RandomGenerator) bytes(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(g.rnd.Uint32())
+	}
+	return b
+}
+
+// uuid returns a random version 4 UUID.
+func (g *RandomGenerator) uuid() string {
+	b := g.bytes(16)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,161 @@
+package avro_test
+
+import (
+	"math/big"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const randomSchema = `{
+	"type": "record",
+	"name": "test",
+	"namespace": "org.hamba.avro",
+	"fields": [
+		{"name": "null", "type": "null"},
+		{"name": "bool", "type": "boolean"},
+		{"name": "int", "type": "int"},
+		{"name": "long", "type": "long"},
+		{"name": "float", "type": "float"},
+		{"name": "double", "type": "double"},
+		{"name": "string", "type": "string"},
+		{"name": "bytes", "type": "bytes"},
+		{"name": "fixed", "type": {"type": "fixed", "name": "fixed", "size": 4}},
+		{"name": "enum", "type": {"type": "enum", "name": "enum", "symbols": ["A", "B", "C"]}},
+		{"name": "array", "type": {"type": "array", "items": "int"}},
+		{"name": "map", "type": {"type": "map", "values": "string"}},
+		{"name": "union", "type": ["null", "int", "string", "org.hamba.avro.enum"]},
+		{"name": "date", "type": {"type": "int", "logicalType": "date"}},
+		{"name": "time_millis", "type": {"type": "int", "logicalType": "time-millis"}},
+		{"name": "time_micros", "type": {"type": "long", "logicalType": "time-micros"}},
+		{"name": "timestamp_millis", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "timestamp_micros", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+		{"name": "local_timestamp_millis", "type": {"type": "long", "logicalType": "local-timestamp-millis"}},
+		{"name": "local_timestamp_micros", "type": {"type": "long", "logicalType": "local-timestamp-micros"}},
+		{"name": "uuid", "type": {"type": "string", "logicalType": "uuid"}},
+		{"name": "decimal", "type": {"type": "bytes", "logicalType": "decimal", "precision": 6, "scale": 2}},
+		{"name": "fixed_decimal", "type": {"type": "fixed", "name": "dec", "size": 6, "logicalType": "decimal", "precision": 8, "scale": 3}},
+		{"name": "duration", "type": {"type": "fixed", "name": "duration", "size": 12, "logicalType": "duration"}}
+	]
+}`
+
+func TestRandom(t *testing.T) {
+	schema := avro.MustParse(randomSchema)
+	uuidRe := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	maxDecimal := big.NewRat(10000, 1)
+
+	gen := avro.NewRandomGenerator(schema, 1)
+	unions := map[string]int{}
+	for range 200 {
+		v, err := gen.Next()
+		require.NoError(t, err)
+
+		b, err := avro.Marshal(schema, v)
+		require.NoError(t, err)
+		var got any
+		require.NoError(t, avro.Unmarshal(schema, b, &got))
+		assert.Equal(t, v, got)
+
+		m := v.(map[string]any)
+		assert.Regexp(t, uuidRe, m["uuid"])
+		assert.Contains(t, []string{"A", "B", "C"}, m["enum"])
+		dec := new(big.Rat).Abs(m["decimal"].(*big.Rat))
+		assert.Equal(t, -1, dec.Cmp(maxDecimal))
+		date := m["date"].(time.Time)
+		assert.Equal(t, date, date.Truncate(24*time.Hour))
+		assert.Less(t, m["time_millis"].(time.Duration), 24*time.Hour)
+
+		if u, ok := m["union"].(map[string]any); ok {
+			for k := range u {
+				unions[k]++
+			}
+		} else {
+			assert.Nil(t, m["union"])
+			unions["null"]++
+		}
+	}
+	assert.Len(t, unions, 4)
+}
+
+func TestRandom_Deterministic(t *testing.T) {
+	schema := avro.MustParse(randomSchema)
+
+	got1, err := avro.Random(schema, 42)
+	require.NoError(t, err)
+	got2, err := avro.Random(schema, 42)
+	require.NoError(t, err)
+	got3, err := avro.Random(schema, 43)
+	require.NoError(t, err)
+
+	assert.Equal(t, got1, got2)
+	assert.NotEqual(t, got1, got3)
+}
+
+func TestRandom_MaxLength(t *testing.T) {
+	schema := avro.MustParse(`{"type": "array", "items": "string"}`)
+
+	gen := avro.NewRandomGenerator(schema, 1, avro.WithRandomMaxLength(3))
+	for range 50 {
+		v, err := gen.Next()
+		require.NoError(t, err)
+		arr := v.([]any)
+		assert.LessOrEqual(t, len(arr), 3)
+		for _, s := range arr {
+			assert.LessOrEqual(t, len(s.(string)), 3)
+		}
+	}
+}
+
+func TestRandom_RecursiveRecord(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "node",
+		"fields": [
+			{"name": "children", "type": {"type": "array", "items": "node"}},
+			{"name": "next", "type": ["node", "null"]}
+		]
+	}`)
+
+	gen := avro.NewRandomGenerator(schema, 1, avro.WithRandomMaxDepth(3))
+	for range 20 {
+		v, err := gen.Next()
+		require.NoError(t, err)
+		assert.LessOrEqual(t, recordDepth(v), 4)
+
+		_, err = avro.Marshal(schema, v)
+		require.NoError(t, err)
+	}
+}
+
+func TestRandom_RecursiveRecordWithoutEnd(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "node",
+		"fields": [
+			{"name": "next", "type": ["node"]}
+		]
+	}`)
+
+	_, err := avro.Random(schema, 1, avro.WithRandomMaxDepth(2))
+
+	assert.ErrorContains(t, err, "recursive record node cannot end within max depth 2")
+}
+
+func recordDepth(v any) int {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return 0
+	}
+	depth := 0
+	for _, child := range m["children"].([]any) {
+		depth = max(depth, recordDepth(child))
+	}
+	if next, ok := m["next"].(map[string]any); ok {
+		depth = max(depth, recordDepth(next["node"]))
+	}
+	return depth + 1
+}
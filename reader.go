@@ -32,6 +32,15 @@ type Reader struct {
 	head   int
 	tail   int
 	Error  error
+
+	// offset is the input offset of the buffer.
+	offset int64
+
+	// depth and allocated are the nesting depth and the allocated size of
+	// the value being decoded, limited by Config.MaxDepth and
+	// Config.MaxTotalAllocation.
+	depth     int
+	allocated int64
 }
 
 // NewReader creates a new Reader.
@@ -57,9 +66,75 @@ func (r *Reader) Reset(b []byte) *Reader {
 	r.buf = b
 	r.head = 0
 	r.tail = len(b)
+	r.offset = 0
+	r.resetLimits()
 	return r
 }
 
+// resetLimits resets the decoding limits for the next value.
+func (r *Reader) resetLimits() {
+	r.depth = 0
+	r.allocated = 0
+}
+
+// startValue resets the allocated size if a new top level value is about to
+// be decoded, so that values read one after another from the same Reader
+// are limited separately.
+func (r *Reader) startValue() {
+	if r.depth == 0 {
+		r.allocated = 0
+	}
+}
+
+// enterNested increases the nesting depth of the value being decoded,
+// reporting an error if it exceeds Config.MaxDepth. It must be followed by
+// a call to leaveNested if it returns true.
+func (r *Reader) enterNested() bool {
+	if !r.cfg.limits {
+		return true
+	}
+
+	r.depth++
+	if maxDepth := r.cfg.getMaxDepth(); maxDepth > 0 && r.depth > maxDepth {
+		r.depth--
+		r.reportLimit("MaxDepth", int64(maxDepth))
+		return false
+	}
+	return true
+}
+
+// leaveNested decreases the nesting depth of the value being decoded.
+func (r *Reader) leaveNested() {
+	if r.cfg.limits {
+		r.depth--
+	}
+}
+
+// allocate records the allocation of n values of the given size, reporting
+// an error if the total size exceeds Config.MaxTotalAllocation.
+func (r *Reader) allocate(n, size int64) bool {
+	if !r.cfg.limits {
+		return true
+	}
+	maxAlloc := r.cfg.getMaxTotalAllocation()
+	if maxAlloc <= 0 {
+		return true
+	}
+	if size > 0 && n > (maxAlloc-r.allocated)/size {
+		r.reportLimit("MaxTotalAllocation", maxAlloc)
+		return false
+	}
+	r.allocated += n * size
+	return true
+}
+
+func (r *Reader) reportLimit(limit string, maxVal int64) {
+	if r.Error != nil && !errors.Is(r.Error, io.EOF) {
+		return
+	}
+	r.Error = &LimitError{Limit: limit, Max: maxVal}
+}
+
 // ReportError record an error in iterator instance with current position.
 func (r *Reader) ReportError(operation, msg string) {
 	if r.Error != nil && !errors.Is(r.Error, io.EOF) {
@@ -69,6 +144,51 @@ func (r *Reader) ReportError(operation, msg string) {
 	r.Error = fmt.Errorf("avro: %s: %s", operation, msg)
 }
 
+// inputOffset returns the offset in the input of the next byte to read.
+func (r *Reader) inputOffset() int64 {
+	return r.offset + int64(r.head)
+}
+
+// wrapDecodeError adds the path segment of the value being decoded to the
+// reader error, recording the location of the error if not yet recorded.
+// The segment is a field name or an index such as [3], and start is the
+// input offset of the value.
+func (r *Reader) wrapDecodeError(segment string, schema Schema, start int64) {
+	var derr *DecodeError
+	if !errors.As(r.Error, &derr) {
+		r.Error = &DecodeError{Path: segment, Offset: start, Schema: schema, Err: r.Error}
+		return
+	}
+
+	switch {
+	case derr.Path == "":
+		derr.Path = segment
+	case strings.HasPrefix(derr.Path, "["):
+		derr.Path = segment + derr.Path
+	default:
+		derr.Path = segment + "." + derr.Path
+	}
+}
+
+// finishDecodeError records the location of the reader error if not yet
+// recorded, where start is the input offset of the decoded value. Direct EOF
+// errors and unsupported type errors are not wrapped.
+func (r *Reader) finishDecodeError(schema Schema, start int64) {
+	//nolint:errorlint // Only direct EOF errors should be kept.
+	if r.Error == nil || r.Error == io.EOF {
+		return
+	}
+	//nolint:errorlint // Top level type errors are returned as is.
+	if terr, ok := r.Error.(*typeError); ok {
+		r.Error = terr.err
+		return
+	}
+	if errors.As(r.Error, new(*DecodeError)) {
+		return
+	}
+	r.Error = &DecodeError{Offset: start, Schema: schema, Err: r.Error}
+}
+
 func (r *Reader) loadMore() bool {
 	if r.reader == nil {
 		if r.Error == nil {
@@ -90,6 +210,7 @@ func (r *Reader) loadMore() bool {
 			continue
 		}
 
+		r.offset += int64(r.tail)
 		r.head = 0
 		r.tail = n
 		return true
@@ -293,6 +414,18 @@ func (r *Reader) readBytes(op string) []byte {
 		return nil
 	}
 
+	// The input is a byte slice that may be aliased, as it is never reused.
+	// Truncated input is read by the copying path, reporting the same error.
+	if r.reader == nil && r.cfg.getZeroCopy() && size <= r.tail-r.head {
+		b := r.buf[r.head : r.head+size : r.head+size]
+		r.head += size
+		return b
+	}
+
+	if !r.allocate(int64(size), 1) {
+		return nil
+	}
+
 	// The bytes are entirely in the buffer and of a reasonable size.
 	// Use the byte slab.
 	if r.head+size <= r.tail && size <= 1024 {
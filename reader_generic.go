@@ -1,13 +1,19 @@
 package avro
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"strconv"
 	"time"
+	"unsafe"
 )
 
 // ReadNext reads the next Avro element as a generic interface.
 func (r *Reader) ReadNext(schema Schema) any {
+	r.startValue()
+
 	var ls LogicalSchema
 	lts, ok := schema.(LogicalTypeSchema)
 	if ok {
@@ -63,11 +69,21 @@ func (r *Reader) ReadNext(schema Schema) any {
 		}
 		return r.ReadBytes()
 	case Record:
+		if !r.enterNested() {
+			return nil
+		}
+
 		fields := schema.(*RecordSchema).Fields()
 		obj := make(map[string]any, len(fields))
 		for _, field := range fields {
+			start := r.inputOffset()
 			obj[field.Name()] = r.ReadNext(field.Type())
+			if r.Error != nil && !errors.Is(r.Error, io.EOF) {
+				r.wrapDecodeError(field.Name(), field.Type(), start)
+				break
+			}
 		}
+		r.leaveNested()
 		return obj
 	case Ref:
 		return r.ReadNext(schema.(*RefSchema).Schema())
@@ -80,20 +96,54 @@ func (r *Reader) ReadNext(schema Schema) any {
 		}
 		return symbols[idx]
 	case Array:
+		if !r.enterNested() {
+			return nil
+		}
+
 		arr := []any{}
-		r.ReadArrayCB(func(r *Reader) bool {
-			elem := r.ReadNext(schema.(*ArraySchema).Items())
+		items := schema.(*ArraySchema).Items()
+		r.readArrayCB(func(r *Reader) bool {
+			if r.Error != nil {
+				return false
+			}
+			if !r.allocate(1, int64(unsafe.Sizeof(any(nil)))) {
+				return false
+			}
+			start := r.inputOffset()
+			elem := r.ReadNext(items)
+			if r.Error != nil && !errors.Is(r.Error, io.EOF) {
+				r.wrapDecodeError("["+strconv.Itoa(len(arr))+"]", items, start)
+				return false
+			}
 			arr = append(arr, elem)
 			return true
 		})
+		r.leaveNested()
 		return arr
 	case Map:
+		if !r.enterNested() {
+			return nil
+		}
+
 		obj := map[string]any{}
-		r.ReadMapCB(func(r *Reader, field string) bool {
-			elem := r.ReadNext(schema.(*MapSchema).Values())
+		values := schema.(*MapSchema).Values()
+		r.readMapCB(func(r *Reader, field string) bool {
+			if r.Error != nil {
+				return false
+			}
+			if !r.allocate(1, int64(unsafe.Sizeof("")+unsafe.Sizeof(any(nil)))) {
+				return false
+			}
+			start := r.inputOffset()
+			elem := r.ReadNext(values)
+			if r.Error != nil && !errors.Is(r.Error, io.EOF) {
+				r.wrapDecodeError("["+strconv.Quote(field)+"]", values, start)
+				return false
+			}
 			obj[field] = elem
 			return true
 		})
+		r.leaveNested()
 		return obj
 	case Union:
 		types := schema.(*UnionSchema).Types()
@@ -113,6 +163,9 @@ func (r *Reader) ReadNext(schema Schema) any {
 		return obj
 	case Fixed:
 		size := schema.(*FixedSchema).Size()
+		if !r.allocate(int64(size), 1) {
+			return nil
+		}
 		obj := make([]byte, size)
 		r.Read(obj)
 		if ls != nil && ls.Type() == Decimal {
@@ -154,6 +207,42 @@ func (r *Reader) ReadMapCB(fn func(*Reader, string) bool) {
 	}
 }
 
+// readArrayCB reads an array with a callback per item, stopping when the
+// callback returns false. The callback must only do so after an error, as
+// the Reader is left within the array.
+func (r *Reader) readArrayCB(fn func(*Reader) bool) {
+	for {
+		l, _ := r.ReadBlockHeader()
+		if l == 0 {
+			break
+		}
+		for range l {
+			if !fn(r) {
+				return
+			}
+		}
+	}
+}
+
+// readMapCB reads a map with a callback per item, stopping when the
+// callback returns false. The callback must only do so after an error, as
+// the Reader is left within the map.
+func (r *Reader) readMapCB(fn func(*Reader, string) bool) {
+	for {
+		l, _ := r.ReadBlockHeader()
+		if l == 0 {
+			break
+		}
+
+		for range l {
+			field := r.ReadString()
+			if !fn(r, field) {
+				return
+			}
+		}
+	}
+}
+
 var byteType = reflect.TypeFor[byte]()
 
 func byteSliceToArray(b []byte, size int) any {
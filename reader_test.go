@@ -721,3 +721,31 @@ func (r *delayedReader) Read(p []byte) (n int, err error) {
 
 	return copy(p, r.b), nil
 }
+
+func TestReader_ReadArrayCBReadsAllItems(t *testing.T) {
+	r := avro.NewReader(bytes.NewReader([]byte{0x04, 0x02, 0x04, 0x00, 0x06}), 10)
+
+	var got []int32
+	r.ReadArrayCB(func(r *avro.Reader) bool {
+		got = append(got, r.ReadInt())
+		return false
+	})
+
+	require.NoError(t, r.Error)
+	assert.Equal(t, []int32{1, 2}, got)
+	assert.Equal(t, int64(3), r.ReadLong())
+}
+
+func TestReader_ReadMapCBReadsAllItems(t *testing.T) {
+	r := avro.NewReader(bytes.NewReader([]byte{0x04, 0x02, 'a', 0x02, 0x02, 'b', 0x04, 0x00, 0x06}), 10)
+
+	got := map[string]int32{}
+	r.ReadMapCB(func(r *avro.Reader, field string) bool {
+		got[field] = r.ReadInt()
+		return false
+	})
+
+	require.NoError(t, r.Error)
+	assert.Equal(t, map[string]int32{"a": 1, "b": 2}, got)
+	assert.Equal(t, int64(3), r.ReadLong())
+}
@@ -0,0 +1,49 @@
+package resolvers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hamba/avro/v2"
+)
+
+// NewDirSchemaStore creates a MemorySchemaStore holding the schemas of all
+// `.avsc` files in dir.
+//
+// Files are parsed in lexical order and share a schema cache, so a schema may
+// reference named types defined in a file that sorts before it.
+func NewDirSchemaStore(dir string) (*MemorySchemaStore, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".avsc") {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+
+	store := NewMemorySchemaStore()
+	cache := &avro.SchemaCache{}
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		b, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, err
+		}
+
+		schema, err := avro.ParseBytesWithCache(b, "", cache)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		if err = store.AddSchema(schema); err != nil {
+			return nil, fmt.Errorf("adding %s: %w", path, err)
+		}
+	}
+	return store, nil
+}
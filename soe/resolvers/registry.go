@@ -0,0 +1,219 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/registry"
+	"github.com/hamba/avro/v2/soe"
+)
+
+// RegistryResolverFunc is a function used to customize the RegistryResolver.
+type RegistryResolverFunc func(*RegistryResolver)
+
+// WithSubjects restricts the subjects indexed by the resolver. By default,
+// all subjects in the registry are indexed.
+func WithSubjects(subjects ...string) RegistryResolverFunc {
+	return func(r *RegistryResolver) {
+		r.subjects = subjects
+	}
+}
+
+// WithMinRefreshInterval sets the minimum time between two refreshes of the
+// fingerprint index, failed or not, defaulting to 30 seconds. Lookups of
+// unknown fingerprints within the interval fail without contacting the registry.
+func WithMinRefreshInterval(d time.Duration) RegistryResolverFunc {
+	return func(r *RegistryResolver) {
+		r.minRefresh = d
+	}
+}
+
+// WithUnknownSchemaTTL sets the time for which a fingerprint that was not
+// found after a refresh, or whose refresh failed, is known to be unknown,
+// defaulting to 5 minutes.
+// Lookups of the fingerprint within this time fail without refreshing
+// the index.
+func WithUnknownSchemaTTL(d time.Duration) RegistryResolverFunc {
+	return func(r *RegistryResolver) {
+		r.unknownTTL = d
+	}
+}
+
+// WithRefreshErrorHandler sets the function called with the errors of the
+// subjects and schema versions that could not be indexed during a refresh
+// triggered by a lookup. These entries are skipped and retried on the next
+// refresh. By default, the errors are discarded.
+func WithRefreshErrorHandler(fn func(error)) RegistryResolverFunc {
+	return func(r *RegistryResolver) {
+		r.errHandler = fn
+	}
+}
+
+const (
+	defaultMinRefreshInterval = 30 * time.Second
+	defaultUnknownSchemaTTL   = 5 * time.Minute
+)
+
+type subjectVersion struct {
+	subject string
+	version int
+}
+
+// RegistryResolver is a resolver that looks up schemas by fingerprint in
+// a schema registry.
+//
+// The registry has no notion of fingerprints, so the resolver builds an index
+// of the schemas of the selected subjects. The index is built on first use and
+// refreshed when a fingerprint is not found in it.
+type RegistryResolver struct {
+	reg        registry.Registry
+	subjects   []string
+	minRefresh time.Duration
+	unknownTTL time.Duration
+	errHandler func(error)
+
+	store MemorySchemaStore
+
+	mu          sync.Mutex // Guards refreshes.
+	seen        map[subjectVersion]struct{}
+	unknown     map[string]time.Time // Fingerprints not found, by lookup time.
+	lastRefresh time.Time
+}
+
+// NewRegistryResolver creates a new RegistryResolver for the given registry.
+func NewRegistryResolver(reg registry.Registry, opts ...RegistryResolverFunc) *RegistryResolver {
+	r := &RegistryResolver{
+		reg:        reg,
+		minRefresh: defaultMinRefreshInterval,
+		unknownTTL: defaultUnknownSchemaTTL,
+		errHandler: func(error) {},
+		seen:       map[subjectVersion]struct{}{},
+		unknown:    map[string]time.Time{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// GetSchema implements SchemaResolver.
+func (r *RegistryResolver) GetSchema(ctx context.Context, fingerprint []byte) (avro.Schema, error) {
+	schema, err := r.store.GetSchema(ctx, fingerprint)
+	if err == nil || !errors.Is(err, soe.ErrUnknownSchema) {
+		return schema, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Another caller may have refreshed the index while we were waiting.
+	if schema, err = r.store.GetSchema(ctx, fingerprint); err == nil {
+		return schema, nil
+	}
+	if !r.lastRefresh.IsZero() && time.Since(r.lastRefresh) < r.minRefresh {
+		return nil, err
+	}
+	if at, ok := r.unknown[string(fingerprint)]; ok && time.Since(at) < r.unknownTTL {
+		return nil, err
+	}
+
+	if rerr := r.refresh(ctx); rerr != nil {
+		var entryErrs *refreshErrors
+		if !errors.As(rerr, &entryErrs) {
+			// The fingerprint is not retried until the TTL expires, even
+			// while the registry is unavailable.
+			r.unknown[string(fingerprint)] = time.Now()
+			return nil, fmt.Errorf("refreshing index: %w", rerr)
+		}
+		for _, entryErr := range entryErrs.errs {
+			r.errHandler(entryErr)
+		}
+	}
+
+	schema, err = r.store.GetSchema(ctx, fingerprint)
+	if errors.Is(err, soe.ErrUnknownSchema) {
+		r.unknown[string(fingerprint)] = time.Now()
+	}
+	return schema, err
+}
+
+// Refresh updates the fingerprint index with any schema versions that were
+// registered since the last refresh, and forgets the unknown fingerprints.
+// Subjects and schema versions that cannot be indexed are skipped, and their
+// errors are returned together once all others are indexed.
+func (r *RegistryResolver) Refresh(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.unknown)
+	return r.refresh(ctx)
+}
+
+// refreshErrors are the errors of the entries skipped during a refresh.
+type refreshErrors struct {
+	errs []error
+}
+
+func (e *refreshErrors) Error() string {
+	return errors.Join(e.errs...).Error()
+}
+
+func (e *refreshErrors) Unwrap() []error {
+	return e.errs
+}
+
+func (r *RegistryResolver) refresh(ctx context.Context) error {
+	// Failed refreshes count towards the minimum refresh interval, so that
+	// an unavailable registry is not contacted on every lookup.
+	r.lastRefresh = time.Now()
+	for fp, at := range r.unknown {
+		if time.Since(at) >= r.unknownTTL {
+			delete(r.unknown, fp)
+		}
+	}
+
+	subjects := r.subjects
+	if len(subjects) == 0 {
+		var err error
+		subjects, err = r.reg.GetSubjects(ctx)
+		if err != nil {
+			return fmt.Errorf("getting subjects: %w", err)
+		}
+	}
+
+	var errs []error
+	for _, subject := range subjects {
+		versions, err := r.reg.GetVersions(ctx, subject)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("getting versions of %q: %w", subject, err))
+			continue
+		}
+
+		for _, version := range versions {
+			key := subjectVersion{subject: subject, version: version}
+			if _, ok := r.seen[key]; ok {
+				continue
+			}
+
+			schema, err := r.reg.GetSchemaByVersion(ctx, subject, version)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("getting schema %q version %d: %w", subject, version, err))
+				continue
+			}
+			if err = r.store.AddSchema(schema); err != nil {
+				errs = append(errs, fmt.Errorf("indexing schema %q version %d: %w", subject, version, err))
+				continue
+			}
+			r.seen[key] = struct{}{}
+		}
+	}
+
+	if len(errs) > 0 {
+		return &refreshErrors{errs: errs}
+	}
+	return nil
+}
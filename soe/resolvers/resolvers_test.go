@@ -0,0 +1,242 @@
+package resolvers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/registry"
+	"github.com/hamba/avro/v2/soe"
+	"github.com/hamba/avro/v2/soe/resolvers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRegistry struct {
+	registry.Registry
+
+	schemas      map[string][]avro.Schema
+	failing      map[string]bool
+	subjectsErr  error
+	calls        int
+	subjectCalls int
+}
+
+func (r *fakeRegistry) GetSubjects(context.Context) ([]string, error) {
+	r.subjectCalls++
+	if r.subjectsErr != nil {
+		return nil, r.subjectsErr
+	}
+	subjects := make([]string, 0, len(r.schemas))
+	for subject := range r.schemas {
+		subjects = append(subjects, subject)
+	}
+	return subjects, nil
+}
+
+func (r *fakeRegistry) GetVersions(_ context.Context, subject string) ([]int, error) {
+	if r.failing[subject] {
+		return nil, errors.New("unavailable")
+	}
+	versions := make([]int, 0, len(r.schemas[subject]))
+	for i := range r.schemas[subject] {
+		versions = append(versions, i+1)
+	}
+	return versions, nil
+}
+
+func (r *fakeRegistry) GetSchemaByVersion(_ context.Context, subject string, version int) (avro.Schema, error) {
+	r.calls++
+	if version < 1 || version > len(r.schemas[subject]) {
+		return nil, errors.New("not found")
+	}
+	return r.schemas[subject][version-1], nil
+}
+
+func fingerprint(t *testing.T, schema avro.Schema) []byte {
+	t.Helper()
+
+	fp, err := soe.ComputeFingerprint(schema)
+	require.NoError(t, err)
+	return fp
+}
+
+func TestRegistryResolver_GetSchema(t *testing.T) {
+	s1 := avro.MustParse(`{"type":"record","name":"one","fields":[{"name":"a","type":"int"}]}`)
+	s2 := avro.MustParse(`{"type":"record","name":"two","fields":[{"name":"b","type":"string"}]}`)
+	reg := &fakeRegistry{schemas: map[string][]avro.Schema{"one": {s1}, "two": {s2}}}
+	r := resolvers.NewRegistryResolver(reg)
+
+	got, err := r.GetSchema(context.Background(), fingerprint(t, s2))
+
+	require.NoError(t, err)
+	assert.Equal(t, s2.Fingerprint(), got.Fingerprint())
+}
+
+func TestRegistryResolver_GetSchemaWithSubjects(t *testing.T) {
+	s1 := avro.MustParse(`{"type":"record","name":"one","fields":[{"name":"a","type":"int"}]}`)
+	s2 := avro.MustParse(`{"type":"record","name":"two","fields":[{"name":"b","type":"string"}]}`)
+	reg := &fakeRegistry{schemas: map[string][]avro.Schema{"one": {s1}, "two": {s2}}}
+	r := resolvers.NewRegistryResolver(reg, resolvers.WithSubjects("one"))
+
+	_, err := r.GetSchema(context.Background(), fingerprint(t, s1))
+	require.NoError(t, err)
+
+	_, err = r.GetSchema(context.Background(), fingerprint(t, s2))
+	assert.ErrorIs(t, err, soe.ErrUnknownSchema)
+}
+
+func TestRegistryResolver_GetSchemaRefreshesOnUnknownSchema(t *testing.T) {
+	s1 := avro.MustParse(`{"type":"record","name":"one","fields":[{"name":"a","type":"int"}]}`)
+	s2 := avro.MustParse(`{"type":"record","name":"one","fields":[{"name":"a","type":"int"},{"name":"b","type":"int","default":0}]}`)
+	reg := &fakeRegistry{schemas: map[string][]avro.Schema{"one": {s1}}}
+	r := resolvers.NewRegistryResolver(reg, resolvers.WithMinRefreshInterval(0))
+
+	_, err := r.GetSchema(context.Background(), fingerprint(t, s1))
+	require.NoError(t, err)
+
+	reg.schemas["one"] = append(reg.schemas["one"], s2)
+
+	got, err := r.GetSchema(context.Background(), fingerprint(t, s2))
+
+	require.NoError(t, err)
+	assert.Equal(t, s2.Fingerprint(), got.Fingerprint())
+	assert.Equal(t, 2, reg.calls)
+}
+
+func TestRegistryResolver_GetSchemaHonoursMinRefreshInterval(t *testing.T) {
+	s1 := avro.MustParse(`{"type":"record","name":"one","fields":[{"name":"a","type":"int"}]}`)
+	s2 := avro.MustParse(`{"type":"record","name":"two","fields":[{"name":"b","type":"string"}]}`)
+	reg := &fakeRegistry{schemas: map[string][]avro.Schema{"one": {s1}}}
+	r := resolvers.NewRegistryResolver(reg, resolvers.WithMinRefreshInterval(time.Hour))
+
+	_, err := r.GetSchema(context.Background(), fingerprint(t, s1))
+	require.NoError(t, err)
+
+	reg.schemas["two"] = []avro.Schema{s2}
+
+	_, err = r.GetSchema(context.Background(), fingerprint(t, s2))
+	assert.ErrorIs(t, err, soe.ErrUnknownSchema)
+
+	err = r.Refresh(context.Background())
+	require.NoError(t, err)
+
+	_, err = r.GetSchema(context.Background(), fingerprint(t, s2))
+	assert.NoError(t, err)
+}
+
+func TestNewDirSchemaStore(t *testing.T) {
+	store, err := resolvers.NewDirSchemaStore("testdata/schemas")
+	require.NoError(t, err)
+
+	address := avro.MustParse(`{"type":"record","name":"Address","namespace":"org.example","fields":[{"name":"street","type":"string"}]}`)
+	got, err := store.GetSchema(context.Background(), fingerprint(t, address))
+	require.NoError(t, err)
+	assert.Equal(t, "org.example.Address", got.(avro.NamedSchema).FullName())
+
+	person := avro.MustParse(`{"type":"record","name":"Person","namespace":"org.example","fields":[{"name":"name","type":"string"},{"name":"address","type":{"type":"record","name":"Address","fields":[{"name":"street","type":"string"}]}}]}`)
+	got, err = store.GetSchema(context.Background(), fingerprint(t, person))
+	require.NoError(t, err)
+	assert.Equal(t, "org.example.Person", got.(avro.NamedSchema).FullName())
+}
+
+func TestNewDirSchemaStore_NotFound(t *testing.T) {
+	_, err := resolvers.NewDirSchemaStore("testdata/does-not-exist")
+
+	assert.Error(t, err)
+}
+
+func TestRegistryResolver_GetSchemaDefaultsMinRefreshInterval(t *testing.T) {
+	s1 := avro.MustParse(`{"type":"record","name":"one","fields":[{"name":"a","type":"int"}]}`)
+	s2 := avro.MustParse(`{"type":"record","name":"two","fields":[{"name":"b","type":"string"}]}`)
+	s3 := avro.MustParse(`{"type":"record","name":"three","fields":[{"name":"c","type":"long"}]}`)
+	reg := &fakeRegistry{schemas: map[string][]avro.Schema{"one": {s1}}}
+	r := resolvers.NewRegistryResolver(reg)
+
+	_, err := r.GetSchema(context.Background(), fingerprint(t, s1))
+	require.NoError(t, err)
+	_, err = r.GetSchema(context.Background(), fingerprint(t, s2))
+	assert.ErrorIs(t, err, soe.ErrUnknownSchema)
+	_, err = r.GetSchema(context.Background(), fingerprint(t, s3))
+	assert.ErrorIs(t, err, soe.ErrUnknownSchema)
+
+	assert.Equal(t, 1, reg.subjectCalls)
+}
+
+func TestRegistryResolver_GetSchemaCachesUnknownSchemas(t *testing.T) {
+	s1 := avro.MustParse(`{"type":"record","name":"one","fields":[{"name":"a","type":"int"}]}`)
+	s2 := avro.MustParse(`{"type":"record","name":"two","fields":[{"name":"b","type":"string"}]}`)
+	s3 := avro.MustParse(`{"type":"record","name":"three","fields":[{"name":"c","type":"long"}]}`)
+	reg := &fakeRegistry{schemas: map[string][]avro.Schema{"one": {s1}}}
+	r := resolvers.NewRegistryResolver(reg, resolvers.WithMinRefreshInterval(0))
+
+	for range 3 {
+		_, err := r.GetSchema(context.Background(), fingerprint(t, s2))
+		assert.ErrorIs(t, err, soe.ErrUnknownSchema)
+	}
+	assert.Equal(t, 1, reg.subjectCalls)
+
+	_, err := r.GetSchema(context.Background(), fingerprint(t, s3))
+	assert.ErrorIs(t, err, soe.ErrUnknownSchema)
+	assert.Equal(t, 2, reg.subjectCalls)
+
+	reg.schemas["two"] = []avro.Schema{s2}
+	require.NoError(t, r.Refresh(context.Background()))
+
+	_, err = r.GetSchema(context.Background(), fingerprint(t, s2))
+	assert.NoError(t, err)
+}
+
+func TestRegistryResolver_GetSchemaSkipsFailingSubjects(t *testing.T) {
+	s1 := avro.MustParse(`{"type":"record","name":"one","fields":[{"name":"a","type":"int"}]}`)
+	s2 := avro.MustParse(`{"type":"record","name":"two","fields":[{"name":"b","type":"string"}]}`)
+	reg := &fakeRegistry{
+		schemas: map[string][]avro.Schema{"one": {s1}, "two": {s2}},
+		failing: map[string]bool{"one": true},
+	}
+	var errs []error
+	r := resolvers.NewRegistryResolver(reg, resolvers.WithRefreshErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	got, err := r.GetSchema(context.Background(), fingerprint(t, s2))
+
+	require.NoError(t, err)
+	assert.Equal(t, s2.Fingerprint(), got.Fingerprint())
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], `getting versions of "one": unavailable`)
+
+	err = r.Refresh(context.Background())
+	assert.EqualError(t, err, `getting versions of "one": unavailable`)
+}
+
+func TestRegistryResolver_GetSchemaRateLimitsFailingRefreshes(t *testing.T) {
+	s1 := avro.MustParse(`{"type":"record","name":"one","fields":[{"name":"a","type":"int"}]}`)
+	s2 := avro.MustParse(`{"type":"record","name":"two","fields":[{"name":"b","type":"string"}]}`)
+	reg := &fakeRegistry{subjectsErr: errors.New("unavailable")}
+	r := resolvers.NewRegistryResolver(reg)
+
+	_, err := r.GetSchema(context.Background(), fingerprint(t, s1))
+	assert.EqualError(t, err, "refreshing index: getting subjects: unavailable")
+	_, err = r.GetSchema(context.Background(), fingerprint(t, s1))
+	assert.ErrorIs(t, err, soe.ErrUnknownSchema)
+	_, err = r.GetSchema(context.Background(), fingerprint(t, s2))
+	assert.ErrorIs(t, err, soe.ErrUnknownSchema)
+
+	assert.Equal(t, 1, reg.subjectCalls)
+}
+
+func TestRegistryResolver_GetSchemaCachesUnknownSchemasOnFailingRefresh(t *testing.T) {
+	s1 := avro.MustParse(`{"type":"record","name":"one","fields":[{"name":"a","type":"int"}]}`)
+	reg := &fakeRegistry{subjectsErr: errors.New("unavailable")}
+	r := resolvers.NewRegistryResolver(reg, resolvers.WithMinRefreshInterval(0))
+
+	for range 3 {
+		_, err := r.GetSchema(context.Background(), fingerprint(t, s1))
+		assert.Error(t, err)
+	}
+
+	assert.Equal(t, 1, reg.subjectCalls)
+}
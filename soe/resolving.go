@@ -0,0 +1,87 @@
+package soe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/hamba/avro/v2"
+)
+
+// ResolvingDecoder unmarshals SOE-framed records written with any schema
+// compatible with its reader schema. It uses a schema resolver to lookup the
+// writer schema of every record, and resolves it against the reader schema
+// as described in the Avro schema resolution rules.
+//
+// Resolved schemas are cached per writer fingerprint.
+type ResolvingDecoder struct {
+	api      avro.API
+	schema   avro.Schema
+	resolver SchemaResolver
+	compat   *avro.SchemaCompatibility
+
+	fingerprint []byte
+	resolved    sync.Map // map[uint64]avro.Schema
+}
+
+// NewResolvingDecoder returns a new ResolvingDecoder for a reader schema,
+// a resolver and the default config.
+func NewResolvingDecoder(schema avro.Schema, resolver SchemaResolver) (*ResolvingDecoder, error) {
+	return NewResolvingDecoderWithAPI(schema, resolver, avro.DefaultConfig)
+}
+
+// NewResolvingDecoderWithAPI returns a new ResolvingDecoder for a reader
+// schema, a resolver and an API.
+func NewResolvingDecoderWithAPI(schema avro.Schema, resolver SchemaResolver, api avro.API) (*ResolvingDecoder, error) {
+	fingerprint, err := ComputeFingerprint(schema)
+	if err != nil {
+		return nil, err
+	}
+	return &ResolvingDecoder{
+		api:         api,
+		schema:      schema,
+		resolver:    resolver,
+		compat:      avro.NewSchemaCompatibility(),
+		fingerprint: fingerprint,
+	}, nil
+}
+
+// Decode unmarshals a value from SOE-encoded Avro binary, resolving the
+// schema specified in the SOE header against the reader schema. Fails if the
+// writer schema is not known to the resolver or is not compatible with the
+// reader schema.
+func (d *ResolvingDecoder) Decode(ctx context.Context, data []byte, v any) error {
+	fingerprint, data, err := ParseHeader(data)
+	if err != nil {
+		return err
+	}
+	schema, err := d.resolve(ctx, fingerprint)
+	if err != nil {
+		return err
+	}
+	return d.api.Unmarshal(schema, data, v)
+}
+
+func (d *ResolvingDecoder) resolve(ctx context.Context, fingerprint []byte) (avro.Schema, error) {
+	if bytes.Equal(fingerprint, d.fingerprint) {
+		return d.schema, nil
+	}
+
+	key := binary.LittleEndian.Uint64(fingerprint)
+	if schema, ok := d.resolved.Load(key); ok {
+		return schema.(avro.Schema), nil
+	}
+
+	writer, err := d.resolver.GetSchema(ctx, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("resolver: %w", err)
+	}
+	schema, err := d.compat.Resolve(d.schema, writer)
+	if err != nil {
+		return nil, fmt.Errorf("resolving writer schema %x: %w", fingerprint, err)
+	}
+	d.resolved.Store(key, schema)
+	return schema, nil
+}
@@ -0,0 +1,105 @@
+package soe_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/soe"
+	"github.com/hamba/avro/v2/soe/internal/testdata"
+	"github.com/hamba/avro/v2/soe/resolvers"
+	"github.com/stretchr/testify/require"
+)
+
+// Helper function to create a new ResolvingDecoder with a registry over zero
+// or more writer schemas.
+func newResolvingDecoder(t *testing.T, reader avro.Schema, schemas ...avro.Schema) *soe.ResolvingDecoder {
+	t.Helper()
+
+	store := resolvers.NewMemorySchemaStore()
+	for _, schema := range schemas {
+		err := store.AddSchema(schema)
+		require.NoError(t, err)
+	}
+	decoder, err := soe.NewResolvingDecoder(reader, store)
+	require.NoError(t, err)
+	return decoder
+}
+
+func TestResolvingDecoder_DecodeWithReaderSchema(t *testing.T) {
+	schema := testdata.Dynamic1Schema
+
+	v0 := testdata.Dynamic1{
+		Name: "Bob",
+		Age:  16,
+	}
+	data := encode(t, schema, v0)
+
+	// The reader schema does not need to be known to the resolver.
+	decoder := newResolvingDecoder(t, schema)
+
+	var v1 testdata.Dynamic1
+	err := decoder.Decode(context.Background(), data, &v1)
+
+	require.NoError(t, err)
+	require.Equal(t, v0, v1)
+}
+
+func TestResolvingDecoder_DecodeUnknownSchema(t *testing.T) {
+	writer := avro.MustParse(`{"name":"dynamic1","type":"record","fields":[{"name":"name","type":"string"},{"name":"age","type":"int"},{"name":"hobby","type":"string"}]}`)
+	data := encode(t, writer, map[string]any{"name": "Bob", "age": 16, "hobby": "Dancing"})
+
+	decoder := newResolvingDecoder(t, testdata.Dynamic1Schema)
+
+	var v1 testdata.Dynamic1
+	err := decoder.Decode(context.Background(), data, &v1)
+
+	require.ErrorIs(t, err, soe.ErrUnknownSchema)
+}
+
+func TestResolvingDecoder_DecodeRemovedField(t *testing.T) {
+	writer := avro.MustParse(`{"name":"dynamic1","type":"record","fields":[{"name":"name","type":"string"},{"name":"age","type":"int"},{"name":"hobby","type":"string"}]}`)
+	data := encode(t, writer, map[string]any{"name": "Bob", "age": 16, "hobby": "Dancing"})
+
+	decoder := newResolvingDecoder(t, testdata.Dynamic1Schema, writer)
+
+	var v1 map[string]any
+	err := decoder.Decode(context.Background(), data, &v1)
+
+	// Fields missing from the reader schema are skipped.
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "Bob", "age": 16}, v1)
+}
+
+func TestResolvingDecoder_DecodeAddedFieldAndPromotion(t *testing.T) {
+	reader := avro.MustParse(`{"name":"dynamic1","type":"record","fields":[{"name":"name","type":"string"},{"name":"age","type":"long"},{"name":"hobby","type":"string","default":"None"}]}`)
+	data := encode(t, testdata.Dynamic1Schema, testdata.Dynamic1{
+		Name: "Bob",
+		Age:  16,
+	})
+
+	decoder := newResolvingDecoder(t, reader, testdata.Dynamic1Schema)
+
+	// Decode twice to exercise the resolved schema cache.
+	for range 2 {
+		var v1 map[string]any
+		err := decoder.Decode(context.Background(), data, &v1)
+
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"name": "Bob", "age": int64(16), "hobby": "None"}, v1)
+	}
+}
+
+func TestResolvingDecoder_DecodeIncompatibleSchema(t *testing.T) {
+	data := encode(t, testdata.Dynamic2Schema, testdata.Dynamic2{
+		Key:     "ABC",
+		Enabled: true,
+	})
+
+	decoder := newResolvingDecoder(t, testdata.Dynamic1Schema, testdata.Dynamic2Schema)
+
+	var v1 testdata.Dynamic1
+	err := decoder.Decode(context.Background(), data, &v1)
+
+	require.Error(t, err)
+}
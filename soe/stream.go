@@ -0,0 +1,216 @@
+package soe
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/hamba/avro/v2"
+)
+
+// defaultMaxMessageSize is the default maximum size of a message the
+// StreamReader will read.
+const defaultMaxMessageSize = 64 << 20 // 64 MiB
+
+// StreamWriter writes a sequence of SOE-framed records to an io.Writer.
+// Every message is prefixed by its length as an unsigned varint, and may be
+// encoded with a different schema.
+//
+// A StreamWriter is not safe for concurrent use.
+type StreamWriter struct {
+	w       io.Writer
+	api     avro.API
+	headers map[[32]byte][]byte
+	buf     []byte
+}
+
+// NewStreamWriter returns a new StreamWriter for w and the default config.
+func NewStreamWriter(w io.Writer) *StreamWriter {
+	return NewStreamWriterWithAPI(w, avro.DefaultConfig)
+}
+
+// NewStreamWriterWithAPI returns a new StreamWriter for w and an API.
+func NewStreamWriterWithAPI(w io.Writer, api avro.API) *StreamWriter {
+	return &StreamWriter{
+		w:       w,
+		api:     api,
+		headers: map[[32]byte][]byte{},
+	}
+}
+
+// Encode marshals v with schema and writes it to the stream as an SOE-framed
+// message.
+func (w *StreamWriter) Encode(schema avro.Schema, v any) error {
+	key := schema.Fingerprint()
+	header, ok := w.headers[key]
+	if !ok {
+		var err error
+		header, err = BuildHeader(schema)
+		if err != nil {
+			return err
+		}
+		w.headers[key] = header
+	}
+
+	data, err := w.api.Marshal(schema, v)
+	if err != nil {
+		return err
+	}
+	return w.write(header, data)
+}
+
+// WriteMessage writes an already SOE-framed message to the stream.
+func (w *StreamWriter) WriteMessage(msg []byte) error {
+	if _, _, err := ParseHeader(msg); err != nil {
+		return err
+	}
+	return w.write(nil, msg)
+}
+
+func (w *StreamWriter) write(header, data []byte) error {
+	w.buf = binary.AppendUvarint(w.buf[:0], uint64(len(header)+len(data)))
+	w.buf = append(w.buf, header...)
+	w.buf = append(w.buf, data...)
+	_, err := w.w.Write(w.buf)
+	return err
+}
+
+// Message is an SOE-framed message read from a stream.
+type Message struct {
+	// Fingerprint is the writer schema fingerprint from the SOE header.
+	Fingerprint []byte
+	// Schema is the writer schema, as returned by the SchemaResolver.
+	Schema avro.Schema
+	// Payload is the Avro binary encoded record.
+	Payload []byte
+
+	api avro.API
+}
+
+// Decode unmarshals the message payload into v using the writer schema.
+func (m *Message) Decode(v any) error {
+	return m.api.Unmarshal(m.Schema, m.Payload, v)
+}
+
+// StreamReaderFunc is a function used to customize the StreamReader.
+type StreamReaderFunc func(*StreamReader)
+
+// WithMaxMessageSize sets the maximum size of a message the StreamReader will
+// read, defaulting to 64MiB. It guards against allocating huge buffers for
+// corrupt length prefixes. This can be disabled by setting a negative number.
+func WithMaxMessageSize(size int) StreamReaderFunc {
+	return func(r *StreamReader) {
+		r.maxSize = size
+	}
+}
+
+// StreamReader reads a sequence of SOE-framed records, as written by
+// a StreamWriter, from an io.Reader.
+//
+// A StreamReader is not safe for concurrent use.
+type StreamReader struct {
+	r        *bufio.Reader
+	api      avro.API
+	resolver SchemaResolver
+	maxSize  int
+}
+
+// NewStreamReader returns a new StreamReader for r, a resolver and the
+// default config.
+func NewStreamReader(r io.Reader, resolver SchemaResolver, opts ...StreamReaderFunc) *StreamReader {
+	return NewStreamReaderWithAPI(r, resolver, avro.DefaultConfig, opts...)
+}
+
+// NewStreamReaderWithAPI returns a new StreamReader for r, a resolver and
+// an API.
+func NewStreamReaderWithAPI(r io.Reader, resolver SchemaResolver, api avro.API, opts ...StreamReaderFunc) *StreamReader {
+	sr := &StreamReader{
+		r:        bufio.NewReader(r),
+		api:      api,
+		resolver: resolver,
+		maxSize:  defaultMaxMessageSize,
+	}
+	for _, opt := range opts {
+		opt(sr)
+	}
+	return sr
+}
+
+// ReadMessage returns the next raw SOE-framed message in the stream.
+// It returns io.EOF when the stream ends cleanly between messages.
+func (r *StreamReader) ReadMessage() ([]byte, error) {
+	size, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("reading message length: %w", err)
+	}
+	if r.maxSize >= 0 && size > uint64(r.maxSize) {
+		return nil, fmt.Errorf("message size %d exceeds max size %d", size, r.maxSize)
+	}
+
+	msg := make([]byte, size)
+	if _, err = io.ReadFull(r.r, msg); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("reading message: %w", err)
+	}
+	return msg, nil
+}
+
+// Next returns the next message in the stream with its writer schema
+// resolved. It returns io.EOF when the stream ends cleanly between messages.
+func (r *StreamReader) Next(ctx context.Context) (*Message, error) {
+	msg, err := r.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	return r.resolve(ctx, msg)
+}
+
+// resolve returns the message with its writer schema resolved.
+func (r *StreamReader) resolve(ctx context.Context, msg []byte) (*Message, error) {
+	fingerprint, payload, err := ParseHeader(msg)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := r.resolver.GetSchema(ctx, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("resolver: %w", err)
+	}
+	return &Message{
+		Fingerprint: fingerprint,
+		Schema:      schema,
+		Payload:     payload,
+		api:         r.api,
+	}, nil
+}
+
+// Messages returns an iterator over the messages in the stream. Errors of
+// a message, such as an unknown schema, are yielded and iteration continues
+// with the next message. Iteration stops at the end of the stream, or after
+// an error reading the stream is yielded.
+func (r *StreamReader) Messages(ctx context.Context) iter.Seq2[*Message, error] {
+	return func(yield func(*Message, error) bool) {
+		for {
+			raw, err := r.ReadMessage()
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					yield(nil, err)
+				}
+				return
+			}
+
+			msg, err := r.resolve(ctx, raw)
+			if !yield(msg, err) {
+				return
+			}
+		}
+	}
+}
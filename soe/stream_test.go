@@ -0,0 +1,142 @@
+package soe_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/hamba/avro/v2/soe"
+	"github.com/hamba/avro/v2/soe/internal/testdata"
+	"github.com/hamba/avro/v2/soe/resolvers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream_Roundtrip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := soe.NewStreamWriter(buf)
+
+	v1 := testdata.Dynamic1{Name: "Bob", Age: 16}
+	v2 := testdata.Dynamic2{Key: "ABC", Enabled: true}
+	err := w.Encode(testdata.Dynamic1Schema, v1)
+	require.NoError(t, err)
+	err = w.Encode(testdata.Dynamic2Schema, v2)
+	require.NoError(t, err)
+	err = w.WriteMessage(encode(t, testdata.Dynamic1Schema, testdata.Dynamic1{Name: "Alice", Age: 20}))
+	require.NoError(t, err)
+
+	store := resolvers.NewMemorySchemaStore()
+	require.NoError(t, store.AddSchema(testdata.Dynamic1Schema))
+	require.NoError(t, store.AddSchema(testdata.Dynamic2Schema))
+	r := soe.NewStreamReader(buf, store)
+
+	var got []any
+	for msg, err := range r.Messages(context.Background()) {
+		require.NoError(t, err)
+
+		switch msg.Schema.Fingerprint() {
+		case testdata.Dynamic1Schema.Fingerprint():
+			var v testdata.Dynamic1
+			require.NoError(t, msg.Decode(&v))
+			got = append(got, v)
+		case testdata.Dynamic2Schema.Fingerprint():
+			var v testdata.Dynamic2
+			require.NoError(t, msg.Decode(&v))
+			got = append(got, v)
+		}
+	}
+
+	require.Equal(t, []any{v1, v2, testdata.Dynamic1{Name: "Alice", Age: 20}}, got)
+}
+
+func TestStreamReader_ReadMessageEOF(t *testing.T) {
+	r := soe.NewStreamReader(bytes.NewReader(nil), resolvers.NewMemorySchemaStore())
+
+	_, err := r.ReadMessage()
+
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestStreamReader_ReadMessageTruncated(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := soe.NewStreamWriter(buf)
+	err := w.Encode(testdata.Dynamic1Schema, testdata.Dynamic1{Name: "Bob", Age: 16})
+	require.NoError(t, err)
+
+	r := soe.NewStreamReader(bytes.NewReader(buf.Bytes()[:buf.Len()-2]), resolvers.NewMemorySchemaStore())
+
+	_, err = r.ReadMessage()
+
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestStreamReader_ReadMessageMaxSize(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := soe.NewStreamWriter(buf)
+	err := w.Encode(testdata.Dynamic1Schema, testdata.Dynamic1{Name: "Bob", Age: 16})
+	require.NoError(t, err)
+
+	r := soe.NewStreamReader(bytes.NewReader(buf.Bytes()), resolvers.NewMemorySchemaStore(), soe.WithMaxMessageSize(4))
+
+	_, err = r.ReadMessage()
+
+	require.ErrorContains(t, err, "exceeds max size 4")
+}
+
+func TestStreamReader_MessagesUnknownSchema(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := soe.NewStreamWriter(buf)
+	err := w.Encode(testdata.Dynamic2Schema, testdata.Dynamic2{Key: "ABC", Enabled: true})
+	require.NoError(t, err)
+	err = w.Encode(testdata.Dynamic1Schema, testdata.Dynamic1{Name: "Bob", Age: 16})
+	require.NoError(t, err)
+
+	store := resolvers.NewMemorySchemaStore()
+	require.NoError(t, store.AddSchema(testdata.Dynamic1Schema))
+	r := soe.NewStreamReader(buf, store)
+
+	var (
+		errs []error
+		got  []testdata.Dynamic1
+	)
+	for msg, err := range r.Messages(context.Background()) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var v testdata.Dynamic1
+		require.NoError(t, msg.Decode(&v))
+		got = append(got, v)
+	}
+
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], soe.ErrUnknownSchema)
+	require.Equal(t, []testdata.Dynamic1{{Name: "Bob", Age: 16}}, got)
+}
+
+func TestStreamReader_MessagesTruncated(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := soe.NewStreamWriter(buf)
+	err := w.Encode(testdata.Dynamic1Schema, testdata.Dynamic1{Name: "Bob", Age: 16})
+	require.NoError(t, err)
+
+	store := resolvers.NewMemorySchemaStore()
+	require.NoError(t, store.AddSchema(testdata.Dynamic1Schema))
+	r := soe.NewStreamReader(bytes.NewReader(buf.Bytes()[:buf.Len()-2]), store)
+
+	var errs []error
+	for _, err := range r.Messages(context.Background()) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], io.ErrUnexpectedEOF)
+}
+
+func TestStreamWriter_WriteMessageInvalid(t *testing.T) {
+	w := soe.NewStreamWriter(&bytes.Buffer{})
+
+	err := w.WriteMessage([]byte{0x01, 0x02})
+
+	require.Error(t, err)
+}
@@ -1,6 +1,12 @@
 package soe
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
 	"github.com/hamba/avro/v2"
 )
 
@@ -19,8 +25,9 @@ func GetSchema[T AvroGenerated]() avro.Schema {
 	return val.Schema()
 }
 
-// TypedCodec marshals/unmarshals AvroGenerated values to/from SOE-framed Avro
-// binary payloads. Must be instantiated with a pointer type, e.g.
+// TypedCodec marshals/unmarshals values of type T to/from SOE-framed Avro
+// binary payloads. When created for an AvroGenerated type, it must be
+// instantiated with a pointer type, e.g.
 //
 //	c, _ := NewTypedCodec[*MyType]()
 //	var val MyType
@@ -30,11 +37,20 @@ func GetSchema[T AvroGenerated]() avro.Schema {
 //	var badVal any
 //	c.Encode(badVal) // cannot use badVal (variable of type any)...
 //
-// It is a strongly typed version of soe.Codec.
+// It is a strongly typed version of soe.Codec. The value encoder and decoder
+// are resolved once, when the codec is created.
+//
+// Decode and DecodeUnverified decode into the value v points to, so they
+// return an error if T is not a pointer type. Use DecodeInto or DecodeValue
+// for non-pointer types.
 type TypedCodec[T any] struct {
-	codec *Codec
+	codec  *avro.TypedCodec[T]
+	header []byte
+	isPtr  bool
 }
 
+var errDecodeNonPointer = errors.New("soe: Decode requires a pointer type, use DecodeInto or DecodeValue")
+
 // NewTypedCodec creates a new TypedCodec for type T and the default config.
 func NewTypedCodec[T AvroGenerated]() (*TypedCodec[T], error) {
 	return NewTypedCodecWithAPI[T](avro.DefaultConfig)
@@ -42,29 +58,84 @@ func NewTypedCodec[T AvroGenerated]() (*TypedCodec[T], error) {
 
 // NewTypedCodecWithAPI creates a new TypedCodec for type T and an API.
 func NewTypedCodecWithAPI[T AvroGenerated](api avro.API) (*TypedCodec[T], error) {
-	schema := GetSchema[T]()
-	coder, err := NewCodecWithAPI(schema, api)
+	return NewTypedCodecForSchemaWithAPI[T](GetSchema[T](), api)
+}
+
+// NewTypedCodecForSchema creates a new TypedCodec for type T, a Schema and the
+// default config.
+func NewTypedCodecForSchema[T any](schema avro.Schema) (*TypedCodec[T], error) {
+	return NewTypedCodecForSchemaWithAPI[T](schema, avro.DefaultConfig)
+}
+
+// NewTypedCodecForSchemaWithAPI creates a new TypedCodec for type T, a Schema
+// and an API.
+func NewTypedCodecForSchemaWithAPI[T any](schema avro.Schema, api avro.API) (*TypedCodec[T], error) {
+	// Precompute SOE header
+	header, err := BuildHeader(schema)
+	if err != nil {
+		return nil, err
+	}
+	codec, err := avro.NewTypedCodecWithAPI[T](schema, api)
 	if err != nil {
 		return nil, err
 	}
 	return &TypedCodec[T]{
-		codec: coder,
+		codec:  codec,
+		header: header,
+		isPtr:  reflect.TypeFor[T]().Kind() == reflect.Ptr,
 	}, nil
 }
 
 // Encode marshals a typed value to SOE-encoded Avro binary.
 func (c *TypedCodec[T]) Encode(v T) ([]byte, error) {
-	return c.codec.Encode(v)
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(c.header, data), nil
 }
 
 // Decode unmarshals a typed value from SOE-encoded Avro binary, and fails if
 // the schema fingerprint doesn't match the held schema.
 func (c *TypedCodec[T]) Decode(data []byte, v T) error {
-	return c.codec.Decode(data, v)
+	if !c.isPtr {
+		return errDecodeNonPointer
+	}
+	return c.DecodeInto(data, &v)
+}
+
+// DecodeInto unmarshals a typed value from SOE-encoded Avro binary into the
+// value pointed to by v, and fails if the schema fingerprint doesn't match the
+// held schema.
+func (c *TypedCodec[T]) DecodeInto(data []byte, v *T) error {
+	fingerprint, data, err := ParseHeader(data)
+	if err != nil {
+		return err
+	}
+	expected := c.header[2:]
+	if !bytes.Equal(fingerprint, expected) {
+		return fmt.Errorf("bad fingerprint %x, expected %x", fingerprint, expected)
+	}
+	return c.codec.UnmarshalInto(data, v)
+}
+
+// DecodeValue unmarshals and returns a typed value from SOE-encoded Avro
+// binary, and fails if the schema fingerprint doesn't match the held schema.
+func (c *TypedCodec[T]) DecodeValue(data []byte) (T, error) {
+	var v T
+	err := c.DecodeInto(data, &v)
+	return v, err
 }
 
 // DecodeUnverified unmarshals a typed value from SOE-encoded Avro binary
 // without validating the schema fingerprint.
 func (c *TypedCodec[T]) DecodeUnverified(data []byte, v T) error {
-	return c.codec.DecodeUnverified(data, v)
+	if !c.isPtr {
+		return errDecodeNonPointer
+	}
+	_, data, err := ParseHeader(data)
+	if err != nil {
+		return err
+	}
+	return c.codec.UnmarshalInto(data, &v)
 }
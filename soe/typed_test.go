@@ -146,3 +146,52 @@ func TestTypedCodec_HeaderFormat(t *testing.T) {
 	// Compare to the actual header
 	require.Equal(t, expectedHeader, header)
 }
+
+func TestTypedCodec_ForSchemaRoundtrip(t *testing.T) {
+	codec, err := soe.NewTypedCodecForSchema[testdata.Dynamic1](testdata.Dynamic1Schema)
+	require.NoError(t, err)
+
+	v0 := testdata.Dynamic1{
+		Name: "bob",
+		Age:  14,
+	}
+
+	data, err := codec.Encode(v0)
+	require.NoError(t, err)
+
+	v1, err := codec.DecodeValue(data)
+	require.NoError(t, err)
+	require.Equal(t, v0, v1)
+}
+
+func TestTypedCodec_ForSchemaDecodeNonPointer(t *testing.T) {
+	codec, err := soe.NewTypedCodecForSchema[testdata.Dynamic1](testdata.Dynamic1Schema)
+	require.NoError(t, err)
+
+	data, err := codec.Encode(testdata.Dynamic1{Name: "bob", Age: 14})
+	require.NoError(t, err)
+
+	var v testdata.Dynamic1
+	require.Error(t, codec.Decode(data, v))
+	require.Error(t, codec.DecodeUnverified(data, v))
+
+	require.NoError(t, codec.DecodeInto(data, &v))
+	require.Equal(t, testdata.Dynamic1{Name: "bob", Age: 14}, v)
+}
+
+func TestTypedCodec_ForSchemaTypeMismatch(t *testing.T) {
+	_, err := soe.NewTypedCodecForSchema[testdata.Dynamic2](testdata.Dynamic1Schema)
+
+	require.Error(t, err)
+}
+
+func TestTypedCodec_DecodeValueBadFingerprint(t *testing.T) {
+	codec, err := soe.NewTypedCodecForSchema[testdata.Dynamic1](testdata.Dynamic1Schema)
+	require.NoError(t, err)
+
+	data := encode(t, testdata.Dynamic2Schema, testdata.Dynamic2{Key: "ABC"})
+
+	_, err = codec.DecodeValue(data)
+
+	require.Error(t, err)
+}
@@ -0,0 +1,212 @@
+package avro
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/modern-go/reflect2"
+)
+
+// TypeMismatch describes a Go type that cannot encode or decode its schema.
+type TypeMismatch struct {
+	// Path is the path of the field from the checked type, e.g. items[].price.
+	// Array items and map values are denoted by []. It is empty for the
+	// checked type.
+	Path string
+
+	// Schema is the schema of the field.
+	Schema Schema
+
+	// Type is the Go type of the field, or nil if the field is missing.
+	Type reflect.Type
+
+	// Message describes the mismatch.
+	Message string
+}
+
+// String returns the path and message of the mismatch.
+func (m TypeMismatch) String() string {
+	if m.Path == "" {
+		return m.Message
+	}
+	return m.Path + ": " + m.Message
+}
+
+// CheckType returns every field of typ that cannot encode or decode the schema.
+func CheckType(schema Schema, typ reflect.Type) []TypeMismatch {
+	return DefaultConfig.CheckType(schema, typ)
+}
+
+// CheckType returns every field of typ that cannot encode or decode the schema.
+func (c *frozenConfig) CheckType(schema Schema, typ reflect.Type) []TypeMismatch {
+	return c.checkType(schema, typ, false)
+}
+
+// checkType returns every field of typ that cannot decode the schema, or
+// unless decodeOnly is set, that cannot encode it.
+func (c *frozenConfig) checkType(schema Schema, typ reflect.Type, decodeOnly bool) []TypeMismatch {
+	checker := &typeChecker{cfg: c, decodeOnly: decodeOnly, visited: map[cacheKey]bool{}}
+	checker.check("", schema, typ)
+	return checker.mismatches
+}
+
+type typeChecker struct {
+	cfg        *frozenConfig
+	decodeOnly bool
+	visited    map[cacheKey]bool
+	mismatches []TypeMismatch
+}
+
+func (c *typeChecker) report(path string, schema Schema, typ reflect.Type, msg string) {
+	c.mismatches = append(c.mismatches, TypeMismatch{Path: path, Schema: schema, Type: typ, Message: msg})
+}
+
+func (c *typeChecker) check(path string, schema Schema, typ reflect.Type) {
+	if schema.Type() == Ref {
+		schema = schema.(*RefSchema).Schema()
+	}
+
+	// Empty interfaces are encoded and decoded by the type of their value.
+	if typ.Kind() == reflect.Interface && typ.NumMethod() == 0 && schema.Type() != Union {
+		return
+	}
+
+	switch schema.Type() {
+	case Record:
+		c.checkRecord(path, schema.(*RecordSchema), typ)
+		return
+
+	case Array:
+		if typ.Kind() == reflect.Slice {
+			c.check(path+"[]", schema.(*ArraySchema).Items(), typ.Elem())
+			return
+		}
+
+	case Map:
+		if typ.Kind() == reflect.Map {
+			key := typ.Key()
+			if key.Kind() == reflect.String || reflect2.Type2(key).Implements(textUnmarshalerType) {
+				c.check(path+"[]", schema.(*MapSchema).Values(), typ.Elem())
+				return
+			}
+		}
+
+	case Union:
+		if c.checkUnion(path, schema.(*UnionSchema), typ) {
+			return
+		}
+	}
+
+	c.checkCodecs(path, schema, typ)
+}
+
+func (c *typeChecker) checkRecord(path string, schema *RecordSchema, typ reflect.Type) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		c.checkCodecs(path, schema, typ)
+		return
+	}
+
+	key := cacheKey{fingerprint: schema.Fingerprint(), rtype: reflect2.Type2(typ).RType()}
+	if c.visited[key] {
+		return
+	}
+	c.visited[key] = true
+
+	desc := describeStruct(c.cfg.getTagKey(), reflect2.Type2(typ))
+	for _, field := range schema.Fields() {
+		fieldPath := joinPath(path, field.Name())
+
+		// Aliases only match fields when decoding.
+		sf := desc.Fields.Get(field.Name())
+		if sf == nil {
+			for _, alias := range field.Aliases() {
+				if sf = desc.Fields.Get(alias); sf != nil {
+					break
+				}
+			}
+			// Fields missing from the struct are skipped when decoding.
+			if !field.HasDefault() && !c.decodeOnly {
+				msg := "missing required field"
+				if sf != nil {
+					msg = "cannot encode: " + msg
+				}
+				c.report(fieldPath, field.Type(), nil, msg)
+			}
+		}
+		if sf == nil {
+			continue
+		}
+
+		c.check(fieldPath, field.Type(), sf.Field[len(sf.Field)-1].Type().Type1())
+	}
+}
+
+// checkUnion checks the branches of a union that a type resolves to.
+// It returns false if the type should be checked by its codecs instead.
+func (c *typeChecker) checkUnion(path string, schema *UnionSchema, typ reflect.Type) bool {
+	switch {
+	case typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String && typ.Elem().Kind() == reflect.Interface:
+		// Generic unions are resolved by the value.
+		return true
+
+	case typ.Kind() == reflect.Interface:
+		if typ.NumMethod() > 0 || !c.cfg.config.UnionResolutionError {
+			return false
+		}
+		for _, branch := range schema.Types() {
+			if branch.Type() == Null {
+				continue
+			}
+			name := unionResolutionName(branch)
+			if _, err := c.cfg.resolver.Type(name); err != nil {
+				c.report(path, branch, typ, "no Go type registered for union branch "+name)
+			}
+		}
+		return true
+
+	case reflect2.Type2(typ).Implements(reflect2.Type2(reflect.TypeFor[UnionConverter]())):
+		return false
+
+	case (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice) && schema.Nullable():
+		_, typeIdx := schema.Indices()
+		elem := typ
+		if typ.Kind() == reflect.Ptr {
+			elem = typ.Elem()
+		}
+		c.check(path, schema.Types()[typeIdx], elem)
+		return true
+	}
+
+	return false
+}
+
+// checkCodecs reports the errors of the encoder and decoder of the schema and type.
+func (c *typeChecker) checkCodecs(path string, schema Schema, typ reflect.Type) {
+	rtyp := reflect2.Type2(typ)
+
+	var encErr, decErr error
+	if !c.decodeOnly {
+		if enc, ok := encoderOfType(newEncoderContext(c.cfg), schema, rtyp).(*errorEncoder); ok {
+			encErr = enc.err
+		}
+	}
+	if dec, ok := decoderOfType(newDecoderContext(c.cfg), schema, rtyp).(*errorDecoder); ok {
+		decErr = dec.err
+	}
+
+	switch {
+	case encErr != nil && decErr != nil:
+		c.report(path, schema, typ, trimAvroPrefix(encErr.Error()))
+	case encErr != nil:
+		c.report(path, schema, typ, "cannot encode: "+trimAvroPrefix(encErr.Error()))
+	case decErr != nil:
+		c.report(path, schema, typ, "cannot decode: "+trimAvroPrefix(decErr.Error()))
+	}
+}
+
+func trimAvroPrefix(msg string) string {
+	return strings.TrimPrefix(msg, "avro: ")
+}
@@ -0,0 +1,136 @@
+package avro_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const checkTypeSchema = `{
+	"type": "record",
+	"name": "order",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "note", "type": ["null", "string"]},
+		{"name": "status", "type": {"type": "enum", "name": "status", "symbols": ["NEW", "PAID"]}},
+		{"name": "tags", "type": {"type": "map", "values": "string"}},
+		{"name": "items", "type": {"type": "array", "items": {
+			"type": "record",
+			"name": "item",
+			"fields": [
+				{"name": "name", "type": "string"},
+				{"name": "count", "type": "int"}
+			]
+		}}},
+		{"name": "next", "type": ["null", "order"], "default": null}
+	]
+}`
+
+func TestCheckType(t *testing.T) {
+	type item struct {
+		Name  string `avro:"name"`
+		Count int32  `avro:"count"`
+	}
+	type order struct {
+		ID     int64             `avro:"id"`
+		Note   *string           `avro:"note"`
+		Status string            `avro:"status"`
+		Tags   map[string]string `avro:"tags"`
+		Items  []item            `avro:"items"`
+		Next   *order            `avro:"next"`
+	}
+
+	schema := avro.MustParse(checkTypeSchema)
+
+	got := avro.CheckType(schema, reflect.TypeFor[order]())
+
+	assert.Empty(t, got)
+}
+
+func TestCheckType_Mismatches(t *testing.T) {
+	type item struct {
+		Name  int    `avro:"name"`
+		Count string `avro:"count"`
+	}
+	type order struct {
+		ID     string  `avro:"id"`
+		Note   float64 `avro:"note"`
+		Status int     `avro:"status"`
+		Items  []item  `avro:"items"`
+	}
+
+	schema := avro.MustParse(checkTypeSchema)
+
+	got := avro.CheckType(schema, reflect.TypeFor[*order]())
+
+	require.Len(t, got, 6)
+	paths := make([]string, len(got))
+	for i, m := range got {
+		paths[i] = m.Path
+	}
+	assert.Equal(t, []string{"id", "note", "status", "tags", "items[].name", "items[].count"}, paths)
+	assert.Equal(t, "id: string is unsupported for Avro long", got[0].String())
+	assert.Equal(t, reflect.TypeFor[string](), got[0].Type)
+	assert.Equal(t, avro.Long, got[0].Schema.Type())
+	assert.Equal(t, "note: unknown union type double", got[1].String())
+	assert.Nil(t, got[3].Type)
+	assert.Equal(t, "missing required field", got[3].Message)
+	assert.Equal(t, avro.TypeMismatch{
+		Path:    "items[].count",
+		Schema:  got[5].Schema,
+		Type:    reflect.TypeFor[string](),
+		Message: "string is unsupported for Avro int",
+	}, got[5])
+}
+
+func TestCheckType_AliasOnlyDecodes(t *testing.T) {
+	type testObj struct {
+		Old string `avro:"old"`
+	}
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "r",
+		"fields": [{"name": "new", "type": "string", "aliases": ["old"]}]
+	}`)
+
+	got := avro.CheckType(schema, reflect.TypeFor[testObj]())
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "new: cannot encode: missing required field", got[0].String())
+
+	_, err := avro.Marshal(schema, testObj{Old: "foo"})
+	assert.Error(t, err)
+}
+
+func TestCheckType_Generic(t *testing.T) {
+	schema := avro.MustParse(checkTypeSchema)
+
+	got := avro.CheckType(schema, reflect.TypeFor[map[string]any]())
+
+	assert.Empty(t, got)
+}
+
+func TestCheckType_UnionBranches(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "test",
+		"fields": [
+			{"name": "a", "type": ["null", "string", {"type": "record", "name": "unregistered", "fields": []}]}
+		]
+	}`)
+	type test struct {
+		A any `avro:"a"`
+	}
+
+	got := avro.CheckType(schema, reflect.TypeFor[test]())
+	assert.Empty(t, got)
+
+	api := avro.Config{UnionResolutionError: true}.Freeze()
+	got = api.CheckType(schema, reflect.TypeFor[test]())
+	require.Len(t, got, 1)
+	assert.Equal(t, "a: no Go type registered for union branch unregistered", got[0].String())
+}
@@ -0,0 +1,249 @@
+package avro
+
+import (
+	"errors"
+	"io"
+	"iter"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// TypedCodec encodes and decodes values of type T with a fixed schema.
+//
+// The value encoder and decoder are resolved once, when the codec is created,
+// so type mismatches between T and the schema, as found by CheckType, are
+// reported by the constructor rather than on first use.
+type TypedCodec[T any] struct {
+	cfg    *frozenConfig
+	schema Schema
+	isPtr  bool
+	enc    ValEncoder
+	dec    ValDecoder
+}
+
+// NewTypedCodec returns a new TypedCodec for type T and schema using the default config.
+func NewTypedCodec[T any](schema Schema) (*TypedCodec[T], error) {
+	return NewTypedCodecWithAPI[T](schema, DefaultConfig)
+}
+
+// NewTypedCodecWithAPI returns a new TypedCodec for type T and schema using the given API.
+//
+// Schemas resolved against a writer schema can only be decoded, and require
+// a codec created with NewTypedDecodingCodecWithAPI.
+func NewTypedCodecWithAPI[T any](schema Schema, api API) (*TypedCodec[T], error) {
+	return newTypedCodec[T](schema, api.(*frozenConfig), false)
+}
+
+// NewTypedDecodingCodec returns a new TypedCodec for type T and schema using the default config,
+// that only decodes values.
+func NewTypedDecodingCodec[T any](schema Schema) (*TypedCodec[T], error) {
+	return NewTypedDecodingCodecWithAPI[T](schema, DefaultConfig)
+}
+
+// NewTypedDecodingCodecWithAPI returns a new TypedCodec for type T and schema using the given API,
+// that only decodes values, e.g. with a schema resolved against a writer schema.
+// Its encoding methods return an error.
+func NewTypedDecodingCodecWithAPI[T any](schema Schema, api API) (*TypedCodec[T], error) {
+	return newTypedCodec[T](schema, api.(*frozenConfig), true)
+}
+
+func newTypedCodec[T any](schema Schema, cfg *frozenConfig, decodeOnly bool) (*TypedCodec[T], error) {
+	if mismatches := cfg.checkType(schema, reflect.TypeFor[T](), decodeOnly); len(mismatches) > 0 {
+		return nil, typeMismatchError(mismatches)
+	}
+
+	typ := reflect2.TypeOf((*T)(nil)).(*reflect2.UnsafePtrType).Elem()
+	var enc ValEncoder = &errorEncoder{err: errors.New("avro: typed codec only decodes values")}
+	if !decodeOnly {
+		enc = cfg.EncoderOf(schema, typ)
+	}
+
+	return &TypedCodec[T]{
+		cfg:    cfg,
+		schema: schema,
+		isPtr:  typ.LikePtr(),
+		enc:    enc,
+		dec:    cfg.DecoderOf(schema, reflect2.TypeOf((*T)(nil))),
+	}, nil
+}
+
+// Schema returns the schema of the codec.
+func (c *TypedCodec[T]) Schema() Schema {
+	return c.schema
+}
+
+// Marshal returns the Avro encoding of v.
+func (c *TypedCodec[T]) Marshal(v T) ([]byte, error) {
+	writer := c.cfg.borrowWriter()
+	defer c.cfg.returnWriter(writer)
+
+	c.Write(writer, v)
+	if err := writer.Error; err != nil {
+		return nil, err
+	}
+
+	result := writer.Buffer()
+	copied := make([]byte, len(result))
+	copy(copied, result)
+
+	return copied, nil
+}
+
+// AppendMarshal appends the Avro encoding of v to dst and returns the extended buffer.
+// If an error occurs, dst is returned unchanged.
+func (c *TypedCodec[T]) AppendMarshal(dst []byte, v T) ([]byte, error) {
+	writer := c.cfg.borrowWriter()
+	defer c.cfg.returnWriter(writer)
+
+	return writer.appendTo(dst, func() {
+		c.Write(writer, v)
+	})
+}
+
+// Unmarshal parses the Avro encoded data and returns the decoded value.
+func (c *TypedCodec[T]) Unmarshal(data []byte) (T, error) {
+	var v T
+	err := c.UnmarshalInto(data, &v)
+	return v, err
+}
+
+// UnmarshalInto parses the Avro encoded data and stores the result in the value pointed to by v.
+func (c *TypedCodec[T]) UnmarshalInto(data []byte, v *T) error {
+	if v == nil {
+		return errors.New("avro: can not read into nil pointer")
+	}
+
+	reader := c.cfg.borrowReader(data)
+	defer c.cfg.returnReader(reader)
+
+	c.Read(reader, v)
+	reader.finishDecodeError(c.schema, 0)
+	err := reader.Error
+
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	return err
+}
+
+// Write writes the Avro encoding of v to w.
+func (c *TypedCodec[T]) Write(w *Writer, v T) {
+	ptr := unsafe.Pointer(&v)
+	if c.isPtr {
+		ptr = *(*unsafe.Pointer)(ptr)
+	}
+	c.enc.Encode(ptr, w)
+}
+
+// Read reads an Avro value from r into the value pointed to by v.
+func (c *TypedCodec[T]) Read(r *Reader, v *T) {
+	r.startValue()
+	c.dec.Decode(unsafe.Pointer(v), r)
+}
+
+// NewEncoder returns a new TypedEncoder that writes to w.
+func (c *TypedCodec[T]) NewEncoder(w io.Writer) *TypedEncoder[T] {
+	writer, ok := w.(*Writer)
+	if !ok {
+		writer = NewWriter(w, 512, WithWriterConfig(c.cfg))
+	}
+	return &TypedEncoder[T]{
+		codec: c,
+		w:     writer,
+	}
+}
+
+// NewDecoder returns a new TypedDecoder that reads from r.
+func (c *TypedCodec[T]) NewDecoder(r io.Reader) *TypedDecoder[T] {
+	return &TypedDecoder[T]{
+		codec: c,
+		r:     NewReader(r, 512, WithReaderConfig(c.cfg)),
+	}
+}
+
+// TypedEncoder writes Avro values of type T to an output stream.
+type TypedEncoder[T any] struct {
+	codec *TypedCodec[T]
+	w     *Writer
+}
+
+// Encode writes the Avro encoding of v to the stream.
+func (e *TypedEncoder[T]) Encode(v T) error {
+	e.codec.Write(e.w, v)
+	_ = e.w.Flush()
+	return e.w.Error
+}
+
+// AppendEncode appends the Avro encoding of v to dst and returns the extended buffer,
+// rather than writing it to the stream. If an error occurs, dst is returned unchanged.
+func (e *TypedEncoder[T]) AppendEncode(dst []byte, v T) ([]byte, error) {
+	return e.w.appendTo(dst, func() {
+		e.codec.Write(e.w, v)
+	})
+}
+
+// Reset resets the encoder to write to a new io.Writer.
+func (e *TypedEncoder[T]) Reset(w io.Writer) {
+	e.w.Reset(w)
+}
+
+// TypedDecoder reads and decodes Avro values of type T from an input stream.
+type TypedDecoder[T any] struct {
+	codec *TypedCodec[T]
+	r     *Reader
+}
+
+// Decode reads the next Avro encoded value from its input and returns it.
+func (d *TypedDecoder[T]) Decode() (T, error) {
+	var v T
+	err := d.DecodeInto(&v)
+	return v, err
+}
+
+// DecodeInto reads the next Avro encoded value from its input and stores it in the value pointed to by v.
+func (d *TypedDecoder[T]) DecodeInto(v *T) error {
+	if d.r.head == d.r.tail && d.r.reader != nil {
+		if !d.r.loadMore() {
+			return io.EOF
+		}
+	}
+
+	d.r.resetLimits()
+	start := d.r.inputOffset()
+	d.codec.Read(d.r, v)
+	d.r.finishDecodeError(d.codec.schema, start)
+
+	//nolint:errorlint // Only direct EOF errors should be discarded.
+	if d.r.Error == io.EOF {
+		return nil
+	}
+	return d.r.Error
+}
+
+// All returns an iterator over the values decoded from the stream.
+// Iteration stops at the end of the input, or after the first error is yielded.
+func (d *TypedDecoder[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			v, err := d.Decode()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+func typeMismatchError(mismatches []TypeMismatch) error {
+	msgs := make([]string, len(mismatches))
+	for i, m := range mismatches {
+		msgs[i] = m.String()
+	}
+	return errors.New("avro: " + strings.Join(msgs, "; "))
+}
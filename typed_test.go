@@ -0,0 +1,259 @@
+package avro_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const typedRecordSchema = `{
+	"type": "record",
+	"name": "test",
+	"fields" : [
+		{"name": "a", "type": "long"},
+		{"name": "b", "type": "string"}
+	]
+}`
+
+func TestNewTypedCodec_TypeMismatch(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+	"type": "record",
+	"name": "test",
+	"fields" : [
+		{"name": "a", "type": "long"},
+		{"name": "b", "type": "int"}
+	]
+}`)
+
+	_, err := avro.NewTypedCodec[TestRecord](schema)
+
+	assert.EqualError(t, err, "avro: b: string is unsupported for Avro int")
+}
+
+func TestNewTypedCodec_MissingRequiredField(t *testing.T) {
+	defer ConfigTeardown()
+
+	type partial struct {
+		A int64 `avro:"a"`
+	}
+	schema := avro.MustParse(typedRecordSchema)
+
+	_, err := avro.NewTypedCodec[partial](schema)
+
+	assert.EqualError(t, err, "avro: b: missing required field")
+}
+
+func TestTypedCodec_Roundtrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(typedRecordSchema)
+	codec, err := avro.NewTypedCodec[TestRecord](schema)
+	require.NoError(t, err)
+
+	b, err := codec.Marshal(TestRecord{A: 27, B: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x36, 0x06, 0x66, 0x6f, 0x6f}, b)
+
+	got, err := codec.Unmarshal(b)
+	require.NoError(t, err)
+	assert.Equal(t, TestRecord{A: 27, B: "foo"}, got)
+}
+
+func TestTypedCodec_RoundtripPointer(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(typedRecordSchema)
+	codec, err := avro.NewTypedCodec[*TestRecord](schema)
+	require.NoError(t, err)
+
+	b, err := codec.Marshal(&TestRecord{A: 27, B: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x36, 0x06, 0x66, 0x6f, 0x6f}, b)
+
+	got, err := codec.Unmarshal(b)
+	require.NoError(t, err)
+	assert.Equal(t, &TestRecord{A: 27, B: "foo"}, got)
+}
+
+func TestTypedCodec_RoundtripPrimitive(t *testing.T) {
+	defer ConfigTeardown()
+
+	codec, err := avro.NewTypedCodec[int](avro.MustParse("int"))
+	require.NoError(t, err)
+
+	b, err := codec.Marshal(27)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x36}, b)
+
+	got, err := codec.Unmarshal(b)
+	require.NoError(t, err)
+	assert.Equal(t, 27, got)
+}
+
+func TestTypedCodec_RoundtripMap(t *testing.T) {
+	defer ConfigTeardown()
+
+	codec, err := avro.NewTypedCodec[map[string]any](avro.MustParse(typedRecordSchema))
+	require.NoError(t, err)
+
+	b, err := codec.Marshal(map[string]any{"a": int64(27), "b": "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x36, 0x06, 0x66, 0x6f, 0x6f}, b)
+
+	got, err := codec.Unmarshal(b)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": int64(27), "b": "foo"}, got)
+}
+
+func TestTypedCodec_UnmarshalError(t *testing.T) {
+	defer ConfigTeardown()
+
+	codec, err := avro.NewTypedCodec[TestRecord](avro.MustParse(typedRecordSchema))
+	require.NoError(t, err)
+
+	_, err = codec.Unmarshal([]byte{0x36, 0x06})
+
+	assert.Error(t, err)
+}
+
+func TestTypedEncoderDecoder(t *testing.T) {
+	defer ConfigTeardown()
+
+	codec, err := avro.NewTypedCodec[TestRecord](avro.MustParse(typedRecordSchema))
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	enc := codec.NewEncoder(buf)
+	require.NoError(t, enc.Encode(TestRecord{A: 27, B: "foo"}))
+	require.NoError(t, enc.Encode(TestRecord{A: 1, B: "bar"}))
+
+	dec := codec.NewDecoder(buf)
+	got1, err := dec.Decode()
+	require.NoError(t, err)
+	got2, err := dec.Decode()
+	require.NoError(t, err)
+	_, err = dec.Decode()
+
+	assert.Equal(t, TestRecord{A: 27, B: "foo"}, got1)
+	assert.Equal(t, TestRecord{A: 1, B: "bar"}, got2)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestTypedDecoder_All(t *testing.T) {
+	defer ConfigTeardown()
+
+	codec, err := avro.NewTypedCodec[TestRecord](avro.MustParse(typedRecordSchema))
+	require.NoError(t, err)
+
+	data := []byte{0x36, 0x06, 0x66, 0x6f, 0x6f, 0x02, 0x06, 0x62, 0x61, 0x72}
+	dec := codec.NewDecoder(bytes.NewReader(data))
+
+	var got []TestRecord
+	for rec, err := range dec.All() {
+		require.NoError(t, err)
+		got = append(got, rec)
+	}
+
+	assert.Equal(t, []TestRecord{{A: 27, B: "foo"}, {A: 1, B: "bar"}}, got)
+}
+
+func TestNewTypedCodec_ResolvedSchema(t *testing.T) {
+	defer ConfigTeardown()
+
+	type partial struct {
+		A int64 `avro:"a"`
+	}
+	reader := avro.MustParse(`{"type": "record", "name": "test", "fields": [{"name": "a", "type": "long"}]}`)
+	schema, err := avro.NewSchemaCompatibility().Resolve(reader, avro.MustParse(typedRecordSchema))
+	require.NoError(t, err)
+
+	_, err = avro.NewTypedCodec[partial](schema)
+	require.Error(t, err)
+
+	codec, err := avro.NewTypedDecodingCodec[partial](schema)
+	require.NoError(t, err)
+
+	got, err := codec.Unmarshal([]byte{0x36, 0x06, 0x66, 0x6f, 0x6f})
+	require.NoError(t, err)
+	assert.Equal(t, partial{A: 27}, got)
+
+	_, err = codec.Marshal(got)
+	assert.EqualError(t, err, "avro: typed codec only decodes values")
+}
+
+func TestNewTypedCodec_ResolvedSchemaNestedInArray(t *testing.T) {
+	defer ConfigTeardown()
+
+	type partial struct {
+		A int64 `avro:"a"`
+	}
+	reader := avro.MustParse(`{"type": "array", "items": {"type": "record", "name": "test", "fields": [{"name": "a", "type": "long"}]}}`)
+	writer := avro.MustParse(`{"type": "array", "items": ` + typedRecordSchema + `}`)
+	schema, err := avro.NewSchemaCompatibility().Resolve(reader, writer)
+	require.NoError(t, err)
+
+	_, err = avro.NewTypedCodec[[]partial](schema)
+	require.Error(t, err)
+
+	codec, err := avro.NewTypedDecodingCodec[[]partial](schema)
+	require.NoError(t, err)
+
+	got, err := codec.Unmarshal([]byte{0x02, 0x36, 0x06, 0x66, 0x6f, 0x6f, 0x00})
+	require.NoError(t, err)
+	assert.Equal(t, []partial{{A: 27}}, got)
+
+	_, err = codec.Marshal(got)
+	assert.Error(t, err)
+}
+
+func TestTypedCodec_AppendMarshal(t *testing.T) {
+	defer ConfigTeardown()
+
+	codec, err := avro.NewTypedCodec[TestRecord](avro.MustParse(typedRecordSchema))
+	require.NoError(t, err)
+
+	b, err := codec.AppendMarshal([]byte{0xff}, TestRecord{A: 27, B: "foo"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0x36, 0x06, 0x66, 0x6f, 0x6f}, b)
+}
+
+func TestTypedEncoder_AppendEncode(t *testing.T) {
+	defer ConfigTeardown()
+
+	codec, err := avro.NewTypedCodec[TestRecord](avro.MustParse(typedRecordSchema))
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	enc := codec.NewEncoder(buf)
+	b, err := enc.AppendEncode(nil, TestRecord{A: 27, B: "foo"})
+	require.NoError(t, err)
+	b, err = enc.AppendEncode(b, TestRecord{A: 1, B: "bar"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x36, 0x06, 0x66, 0x6f, 0x6f, 0x02, 0x06, 0x62, 0x61, 0x72}, b)
+	assert.Zero(t, buf.Len())
+}
+
+func TestTypedEncoder_AppendEncodeAfterError(t *testing.T) {
+	defer ConfigTeardown()
+
+	codec, err := avro.NewTypedCodec[map[string]any](avro.MustParse(typedRecordSchema))
+	require.NoError(t, err)
+
+	enc := codec.NewEncoder(&bytes.Buffer{})
+	b, err := enc.AppendEncode([]byte{0xff}, map[string]any{"a": int64(27)})
+	require.Error(t, err)
+	assert.Equal(t, []byte{0xff}, b)
+
+	b, err = enc.AppendEncode(b, map[string]any{"a": int64(27), "b": "foo"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0x36, 0x06, 0x66, 0x6f, 0x6f}, b)
+}
@@ -0,0 +1,456 @@
+package avro
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modern-go/reflect2"
+)
+
+// Violation describes a value that does not conform to its schema.
+type Violation struct {
+	// Path is the path of the value from the validated value,
+	// e.g. order.items[3].price. It is empty for the validated value.
+	Path string
+
+	// Message describes the violation.
+	Message string
+}
+
+// String returns the path and message of the violation.
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// ValidationError is returned when a value does not conform to a schema.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error returns all violations.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.String()
+	}
+	return "avro: invalid value: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks that v conforms to the schema, without encoding it.
+// If it does not, a *ValidationError with all violations is returned.
+func Validate(schema Schema, v any) error {
+	return DefaultConfig.Validate(schema, v)
+}
+
+// Validate checks that v conforms to the schema, without encoding it.
+func (c *frozenConfig) Validate(schema Schema, v any) error {
+	val := &validator{cfg: c}
+	val.validate("", schema, reflect.ValueOf(v))
+	if len(val.violations) > 0 {
+		return &ValidationError{Violations: val.violations}
+	}
+	return nil
+}
+
+type validator struct {
+	cfg        *frozenConfig
+	violations []Violation
+}
+
+func (v *validator) report(path, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) unsupported(path string, schema Schema, val reflect.Value) {
+	v.report(path, "%s is unsupported for Avro %s", val.Type(), schemaTypeName(schema))
+}
+
+func (v *validator) validate(path string, schema Schema, val reflect.Value) {
+	if schema.Type() == Ref {
+		schema = schema.(*RefSchema).Schema()
+	}
+	for val.Kind() == reflect.Interface && !val.IsNil() {
+		val = val.Elem()
+	}
+
+	if schema.Type() == Union {
+		v.validateUnion(path, schema.(*UnionSchema), val)
+		return
+	}
+
+	if isNil(val) {
+		// Nil slices and maps are encoded as empty values.
+		if isEmptyValue(schema, val) {
+			return
+		}
+		if schema.Type() != Null {
+			v.report(path, "nil is not a valid %s", schemaTypeName(schema))
+		}
+		return
+	}
+
+	switch schema.Type() {
+	case Null:
+		v.report(path, "expected nil, got %s", val.Type())
+
+	case Record:
+		v.validateRecord(path, schema.(*RecordSchema), val)
+
+	case Array:
+		if val.Kind() == reflect.Ptr {
+			val = val.Elem()
+		}
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			v.unsupported(path, schema, val)
+			return
+		}
+		items := schema.(*ArraySchema).Items()
+		for i := range val.Len() {
+			v.validate(path+"["+strconv.Itoa(i)+"]", items, val.Index(i))
+		}
+
+	case Map:
+		if val.Kind() == reflect.Ptr {
+			val = val.Elem()
+		}
+		if val.Kind() != reflect.Map || val.Type().Key().Kind() != reflect.String {
+			v.unsupported(path, schema, val)
+			return
+		}
+		values := schema.(*MapSchema).Values()
+		for _, key := range sortedKeys(val) {
+			v.validate(path+"["+strconv.Quote(key.String())+"]", values, val.MapIndex(key))
+		}
+
+	case Enum:
+		v.validateEnum(path, schema.(*EnumSchema), val)
+
+	case Fixed:
+		v.validateFixed(path, schema.(*FixedSchema), val)
+
+	default:
+		v.validatePrimitive(path, schema.(*PrimitiveSchema), val)
+	}
+}
+
+func (v *validator) validateUnion(path string, schema *UnionSchema, val reflect.Value) {
+	if isNil(val) {
+		if !slices.ContainsFunc(schema.Types(), isNullSchema) {
+			v.report(path, "nil is not a valid %s", unionTypeNames(schema))
+		}
+		return
+	}
+
+	typ := val.Type()
+	switch {
+	case typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String && typ.Elem().Kind() == reflect.Interface:
+		// Generic unions are maps of the type name to the value.
+		if val.Len() > 1 {
+			v.report(path, "union map has %d entries, expected at most 1", val.Len())
+			return
+		}
+		if val.Len() == 0 {
+			v.validateUnion(path, schema, reflect.Value{})
+			return
+		}
+		key := val.MapKeys()[0]
+		elem, _ := schema.Types().Get(key.String())
+		if elem == nil {
+			v.report(path, "%s is not a type of %s", key.String(), unionTypeNames(schema))
+			return
+		}
+		v.validate(path, elem, val.MapIndex(key))
+		return
+
+	case typ.Implements(reflect.TypeFor[UnionConverter]()):
+		return
+
+	case (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice) && schema.Nullable():
+		_, typeIdx := schema.Indices()
+		if typ.Kind() == reflect.Ptr {
+			val = val.Elem()
+		}
+		v.validate(path, schema.Types()[typeIdx], val)
+		return
+	}
+
+	names, err := v.cfg.resolver.Name(reflect2.Type2(typ))
+	if err != nil {
+		v.report(path, "%s is not a type of %s", typ, unionTypeNames(schema))
+		return
+	}
+	for _, name := range names {
+		name, _, _ = strings.Cut(name, ":")
+		if elem, _ := schema.Types().Get(name); elem != nil {
+			v.validate(path, elem, val)
+			return
+		}
+	}
+	v.report(path, "%s is not a type of %s", names[0], unionTypeNames(schema))
+}
+
+func (v *validator) validateRecord(path string, schema *RecordSchema, val reflect.Value) {
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			v.report(path, "nil is not a valid %s", schemaTypeName(schema))
+			return
+		}
+		val = val.Elem()
+	}
+
+	switch {
+	case val.Kind() == reflect.Struct:
+		desc := describeStruct(v.cfg.getTagKey(), reflect2.Type2(val.Type()))
+		for _, field := range schema.Fields() {
+			sf := desc.Fields.Get(field.Name())
+			if sf == nil {
+				if !field.HasDefault() {
+					v.report(joinPath(path, field.Name()), "missing required field")
+				}
+				continue
+			}
+			fieldVal, ok := structFieldValue(val, sf)
+			if !ok {
+				// A nil embedded struct encodes as null.
+				fieldVal = reflect.Value{}
+			}
+			v.validate(joinPath(path, field.Name()), field.Type(), fieldVal)
+		}
+
+	case val.Kind() == reflect.Map && val.Type().Key().Kind() == reflect.String && val.Type().Elem().Kind() == reflect.Interface:
+		for _, field := range schema.Fields() {
+			fieldVal := val.MapIndex(reflect.ValueOf(field.Name()))
+			if !fieldVal.IsValid() {
+				if !field.HasDefault() {
+					v.report(joinPath(path, field.Name()), "missing required field")
+				}
+				continue
+			}
+			v.validate(joinPath(path, field.Name()), field.Type(), fieldVal)
+		}
+
+	default:
+		v.unsupported(path, schema, val)
+	}
+}
+
+func (v *validator) validateEnum(path string, schema *EnumSchema, val reflect.Value) {
+	var symbol string
+	switch {
+	case val.Kind() == reflect.String:
+		symbol = val.String()
+	case val.Type().Implements(reflect.TypeFor[encoding.TextMarshaler]()):
+		b, err := val.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			v.report(path, "%v", err)
+			return
+		}
+		symbol = string(b)
+	default:
+		v.unsupported(path, schema, val)
+		return
+	}
+
+	if !slices.Contains(schema.Symbols(), symbol) {
+		v.report(path, "unknown symbol %q of enum %s", symbol, schema.FullName())
+	}
+}
+
+func (v *validator) validateFixed(path string, schema *FixedSchema, val reflect.Value) {
+	if ls := schema.Logical(); ls != nil {
+		switch ls.Type() {
+		case Decimal:
+			if r, ok := ratOf(val); ok {
+				v.validateDecimal(path, ls.(*DecimalLogicalSchema), r)
+				return
+			}
+		case Duration:
+			if val.Type() == reflect.TypeFor[LogicalDuration]() {
+				return
+			}
+		}
+	}
+
+	switch {
+	case val.Kind() == reflect.Array && val.Type().Elem().Kind() == reflect.Uint8:
+		if val.Len() != schema.Size() {
+			v.report(path, "expected %d bytes for fixed %s, got %d", schema.Size(), schema.FullName(), val.Len())
+		}
+	case val.Kind() == reflect.Uint64 && schema.Size() == 8:
+	default:
+		v.unsupported(path, schema, val)
+	}
+}
+
+func (v *validator) validatePrimitive(path string, schema *PrimitiveSchema, val reflect.Value) {
+	var logical LogicalType
+	if ls := schema.Logical(); ls != nil {
+		logical = ls.Type()
+	}
+
+	if val.Kind() == reflect.Struct || val.Kind() == reflect.Ptr {
+		switch {
+		case val.Type().ConvertibleTo(timeType):
+			switch {
+			case schema.Type() == Int && logical == Date,
+				schema.Type() == Long && (logical == TimestampMillis || logical == TimestampMicros ||
+					logical == LocalTimestampMillis || logical == LocalTimestampMicros):
+				return
+			}
+		case logical == Decimal && schema.Type() == Bytes:
+			if r, ok := ratOf(val); ok {
+				v.validateDecimal(path, schema.Logical().(*DecimalLogicalSchema), r)
+				return
+			}
+		}
+		v.unsupported(path, schema, val)
+		return
+	}
+
+	switch schema.Type() {
+	case Boolean:
+		if val.Kind() == reflect.Bool {
+			return
+		}
+	case Int:
+		switch val.Kind() {
+		case reflect.Int, reflect.Int64:
+			if val.Int() < math.MinInt32 || val.Int() > math.MaxInt32 {
+				v.report(path, "%d overflows Avro int", val.Int())
+			}
+			if val.Kind() == reflect.Int || logical == TimeMillis {
+				return
+			}
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+			return
+		}
+	case Long:
+		switch val.Kind() {
+		case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint32:
+			return
+		}
+	case Float:
+		if val.Kind() == reflect.Float32 {
+			return
+		}
+	case Double:
+		if val.Kind() == reflect.Float32 || val.Kind() == reflect.Float64 {
+			return
+		}
+	case String:
+		if val.Kind() == reflect.String {
+			return
+		}
+	case Bytes:
+		if val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+	}
+	v.unsupported(path, schema, val)
+}
+
+func (v *validator) validateDecimal(path string, schema *DecimalLogicalSchema, r *big.Rat) {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(schema.Scale())), nil)
+	unscaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(scale))
+	if !unscaled.IsInt() {
+		v.report(path, "%s has more than %d decimal places", r.FloatString(schema.Scale()+1), schema.Scale())
+		return
+	}
+	if digits := len(new(big.Int).Abs(unscaled.Num()).String()); digits > schema.Precision() {
+		v.report(path, "%s exceeds decimal precision %d", r.FloatString(schema.Scale()), schema.Precision())
+	}
+}
+
+// ratOf returns the big.Rat of a big.Rat or *big.Rat value.
+func ratOf(val reflect.Value) (*big.Rat, bool) {
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() || !val.Type().Elem().ConvertibleTo(ratType) {
+			return nil, false
+		}
+		val = val.Elem()
+	}
+	if !val.Type().ConvertibleTo(ratType) {
+		return nil, false
+	}
+	r := val.Convert(ratType).Interface().(big.Rat)
+	return &r, true
+}
+
+// structFieldValue returns the value of a struct field, following embedded
+// struct pointers. It returns false if an embedded pointer is nil.
+func structFieldValue(val reflect.Value, sf *structField) (reflect.Value, bool) {
+	for _, f := range sf.Field {
+		if val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				return reflect.Value{}, false
+			}
+			val = val.Elem()
+		}
+		val = val.FieldByIndex(f.Index())
+	}
+	return val, true
+}
+
+func isNil(val reflect.Value) bool {
+	if !val.IsValid() {
+		return true
+	}
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return val.IsNil()
+	default:
+		return false
+	}
+}
+
+// isEmptyValue returns true if the nil val is encoded as an empty value of schema.
+func isEmptyValue(schema Schema, val reflect.Value) bool {
+	if !val.IsValid() {
+		return false
+	}
+	switch val.Kind() {
+	case reflect.Slice:
+		return schema.Type() == Bytes || schema.Type() == Array
+	case reflect.Map:
+		return schema.Type() == Map
+	default:
+		return false
+	}
+}
+
+func isNullSchema(schema Schema) bool {
+	return schema.Type() == Null
+}
+
+func unionTypeNames(schema *UnionSchema) string {
+	names := make([]string, len(schema.Types()))
+	for i, typ := range schema.Types() {
+		names[i] = schemaTypeName(typ)
+	}
+	return "union [" + strings.Join(names, ", ") + "]"
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func sortedKeys(val reflect.Value) []reflect.Value {
+	keys := val.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	return keys
+}
@@ -0,0 +1,216 @@
+package avro_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validateSchema = `{
+	"type": "record",
+	"name": "order",
+	"fields": [
+		{"name": "id", "type": "int"},
+		{"name": "status", "type": {"type": "enum", "name": "status", "symbols": ["NEW", "PAID"]}},
+		{"name": "hash", "type": {"type": "fixed", "name": "hash", "size": 4}},
+		{"name": "note", "type": ["null", "string"]},
+		{"name": "created", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "tags", "type": {"type": "map", "values": "string"}, "default": {}},
+		{"name": "items", "type": {"type": "array", "items": {
+			"type": "record",
+			"name": "item",
+			"fields": [
+				{"name": "name", "type": "string"},
+				{"name": "price", "type": {"type": "bytes", "logicalType": "decimal", "precision": 4, "scale": 2}}
+			]
+		}}}
+	]
+}`
+
+type validateItem struct {
+	Name  string   `avro:"name"`
+	Price *big.Rat `avro:"price"`
+}
+
+type validateOrder struct {
+	ID      int            `avro:"id"`
+	Status  string         `avro:"status"`
+	Hash    [4]byte        `avro:"hash"`
+	Note    *string        `avro:"note"`
+	Created time.Time      `avro:"created"`
+	Items   []validateItem `avro:"items"`
+}
+
+func TestValidate_Struct(t *testing.T) {
+	schema := avro.MustParse(validateSchema)
+
+	order := validateOrder{
+		ID:      1,
+		Status:  "NEW",
+		Created: time.Now(),
+		Items:   []validateItem{{Name: "foo", Price: big.NewRat(1234, 100)}},
+	}
+
+	err := avro.Validate(schema, order)
+
+	require.NoError(t, err)
+	_, err = avro.Marshal(schema, order)
+	require.NoError(t, err)
+}
+
+func TestValidate_StructViolations(t *testing.T) {
+	schema := avro.MustParse(validateSchema)
+
+	order := &validateOrder{
+		ID:      1 << 40,
+		Status:  "SHIPPED",
+		Created: time.Now(),
+		Items: []validateItem{
+			{Name: "foo", Price: big.NewRat(1234, 100)},
+			{Name: "bar", Price: big.NewRat(12345, 100)},
+			{Name: "baz", Price: big.NewRat(1, 1000)},
+		},
+	}
+
+	err := avro.Validate(schema, order)
+
+	var verr *avro.ValidationError
+	require.True(t, errors.As(err, &verr))
+	assert.Equal(t, []avro.Violation{
+		{Path: "id", Message: "1099511627776 overflows Avro int"},
+		{Path: "status", Message: `unknown symbol "SHIPPED" of enum status`},
+		{Path: "items[1].price", Message: "123.45 exceeds decimal precision 4"},
+		{Path: "items[2].price", Message: "0.001 has more than 2 decimal places"},
+	}, verr.Violations)
+	assert.Equal(t, `avro: invalid value: id: 1099511627776 overflows Avro int; `+
+		`status: unknown symbol "SHIPPED" of enum status; `+
+		`items[1].price: 123.45 exceeds decimal precision 4; `+
+		`items[2].price: 0.001 has more than 2 decimal places`, err.Error())
+}
+
+func TestValidate_Generic(t *testing.T) {
+	schema := avro.MustParse(validateSchema)
+
+	order := map[string]any{
+		"id":      1,
+		"status":  "PAID",
+		"hash":    [4]byte{1, 2, 3, 4},
+		"note":    map[string]any{"string": "foo"},
+		"created": time.Now(),
+		"items": []any{
+			map[string]any{"name": "foo", "price": big.NewRat(1, 2)},
+		},
+	}
+
+	err := avro.Validate(schema, order)
+
+	require.NoError(t, err)
+	_, err = avro.Marshal(schema, order)
+	require.NoError(t, err)
+}
+
+func TestValidate_GenericViolations(t *testing.T) {
+	schema := avro.MustParse(validateSchema)
+
+	order := map[string]any{
+		"id":      "1",
+		"hash":    [3]byte{1, 2, 3},
+		"note":    map[string]any{"int": 1},
+		"created": time.Now(),
+		"tags":    map[string]any{"a": "foo", "b": 2},
+		"items": []any{
+			map[string]any{"name": "foo", "price": big.NewRat(1, 2)},
+			map[string]any{"price": big.NewRat(1, 2)},
+		},
+	}
+
+	err := avro.Validate(schema, order)
+
+	var verr *avro.ValidationError
+	require.True(t, errors.As(err, &verr))
+	assert.Equal(t, []avro.Violation{
+		{Path: "id", Message: "string is unsupported for Avro int"},
+		{Path: "status", Message: "missing required field"},
+		{Path: "hash", Message: "expected 4 bytes for fixed hash, got 3"},
+		{Path: "note", Message: "int is not a type of union [null, string]"},
+		{Path: "tags[\"b\"]", Message: "int is unsupported for Avro string"},
+		{Path: "items[1].name", Message: "missing required field"},
+	}, verr.Violations)
+}
+
+func TestValidate_Union(t *testing.T) {
+	schema := avro.MustParse(`["null", "int", "string"]`)
+
+	tests := []struct {
+		name    string
+		value   any
+		wantErr string
+	}{
+		{name: "nil", value: nil},
+		{name: "nil pointer", value: (*int)(nil)},
+		{name: "resolved type", value: "foo"},
+		{name: "generic", value: map[string]any{"int": 1}},
+		{name: "generic null", value: map[string]any{}},
+		{name: "unknown type", value: 1.5, wantErr: "double is not a type of union [null, int, string]"},
+		{name: "generic unknown type", value: map[string]any{"long": 1}, wantErr: "long is not a type of union [null, int, string]"},
+		{name: "generic multiple entries", value: map[string]any{"int": 1, "string": "foo"}, wantErr: "union map has 2 entries, expected at most 1"},
+		{name: "generic invalid value", value: map[string]any{"int": "foo"}, wantErr: "string is unsupported for Avro int"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := avro.Validate(schema, test.value)
+
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, "avro: invalid value: "+test.wantErr)
+		})
+	}
+}
+
+func TestValidate_NilSlicesAndMaps(t *testing.T) {
+	type testObj struct {
+		B []byte            `avro:"b"`
+		A []int             `avro:"a"`
+		M map[string]string `avro:"m"`
+	}
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "test",
+		"fields": [
+			{"name": "b", "type": "bytes"},
+			{"name": "a", "type": {"type": "array", "items": "int"}},
+			{"name": "m", "type": {"type": "map", "values": "string"}}
+		]
+	}`)
+
+	err := avro.Validate(schema, testObj{})
+	require.NoError(t, err)
+
+	_, err = avro.Marshal(schema, testObj{})
+	assert.NoError(t, err)
+}
+
+func TestValidate_NilPointer(t *testing.T) {
+	schema := avro.MustParse(`{"type": "array", "items": "int"}`)
+
+	err := avro.Validate(schema, (*[]int)(nil))
+
+	assert.EqualError(t, err, "avro: invalid value: nil is not a valid array")
+}
+
+func TestValidate_NonNullableUnion(t *testing.T) {
+	schema := avro.MustParse(`["int", "string"]`)
+
+	err := avro.Validate(schema, nil)
+
+	assert.EqualError(t, err, "avro: invalid value: nil is not a valid union [int, string]")
+}
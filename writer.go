@@ -81,6 +81,22 @@ func (w *Writer) Flush() error {
 	return nil
 }
 
+// appendTo calls write with dst as the Writer buffer, returning dst with the
+// written bytes appended. The Writer buffer and error are left unchanged, so
+// a failed append does not affect later appends or writes.
+func (w *Writer) appendTo(dst []byte, write func()) ([]byte, error) {
+	buf, werr := w.buf, w.Error
+	w.buf, w.Error = dst, nil
+	write()
+	out, err := w.buf, w.Error
+	w.buf, w.Error = buf, werr
+
+	if err != nil {
+		return dst, err
+	}
+	return out, nil
+}
+
 func (w *Writer) writeByte(b byte) {
 	w.buf = append(w.buf, b)
 }